@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// askCommand answers the question given on the command line
+var askCommand = &command{
+	name:    "ask",
+	args:    `"question"`,
+	summary: "Ask God a question. The same question always gets the same answer.",
+	setup:   setupAsk,
+}
+
+// setupAsk defines the ask flags
+func setupAsk(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "amount", "tz", "no-wrap", "color", "save", "history-file")
+	daily := fs.Bool("daily", false, "Give an answer that changes once per day (see -tz)")
+
+	return func(args []string) error {
+		if err := g.validateAmount(); err != nil {
+			return err
+		}
+		loc, err := g.location()
+		if err != nil {
+			return err
+		}
+		terminal, err := g.terminalOptions(os.Stdout)
+		if err != nil {
+			return err
+		}
+		history, err := g.history()
+		if err != nil {
+			return err
+		}
+
+		question := strings.Join(args, " ")
+		god, err := internal.NewGod(g.amount)
+		if err != nil {
+			return fmt.Errorf("failed to initialize God Says %w", err)
+		}
+
+		salt := ""
+		if *daily {
+			salt = internal.DateSalt(time.Now().In(loc))
+		}
+		seed, err := internal.QuestionSeed(question, salt)
+		if err != nil {
+			return err
+		}
+		answer, err := god.SpeakSeeded(g.amount, seed)
+		if err != nil {
+			return err
+		}
+		record(history, internal.HistoryEntry{Source: "cli", Kind: "ask", GodSays: answer, List: server.DefaultList, Amount: g.amount, Seed: &seed, Question: question})
+
+		fmt.Println(internal.FormatTerminal(strings.Fields(answer), terminal))
+		return nil
+	}
+}
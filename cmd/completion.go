@@ -0,0 +1,251 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// completionCommand prints shell completion scripts
+var completionCommand = &command{
+	name:    "completion",
+	args:    "bash|zsh|fish",
+	summary: "Print the shell completion script for bash, zsh or fish",
+	setup:   setupCompletion,
+}
+
+// shells maps the supported shells to their script generators
+var shells = map[string]func() string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+// fileFlags are the flags whose values are paths
+var fileFlags = map[string]bool{
+	"image":          true,
+	"song":           true,
+	"qr":             true,
+	"history":        true,
+	"history-file":   true,
+	"favorites-file": true,
+	"data-dir":       true,
+	"remote-ca":      true,
+	"remote-cert":    true,
+	"remote-key":     true,
+}
+
+// setupCompletion defines the completion flags
+func setupCompletion(fs *flag.FlagSet, g *globals) func(args []string) error {
+	return func(args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			return errUsage
+		}
+		generate, ok := shells[args[0]]
+		if !ok {
+			return fmt.Errorf("unsupported shell %q, use bash, zsh or fish", args[0])
+		}
+		fmt.Print(generate())
+		return nil
+	}
+}
+
+// commandFlags returns the flags of cmd in lexical order
+func commandFlags(cmd *command) []*flag.Flag {
+	fs := newFlagSet(cmd)
+	cmd.setup(fs, defaultGlobals())
+	var flags []*flag.Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, f)
+	})
+	return flags
+}
+
+// isBoolFlag reports whether f takes no value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// commandNames returns the names of all commands, including help
+func commandNames() []string {
+	names := make([]string, 0, len(commands)+1)
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	return append(names, "help")
+}
+
+// bashCompletion generates the bash completion script
+func bashCompletion() string {
+	var b strings.Builder
+	var valueFlags []string
+	seen := map[string]bool{}
+	for _, cmd := range commands {
+		for _, f := range commandFlags(cmd) {
+			if !isBoolFlag(f) && !seen[f.Name] {
+				seen[f.Name] = true
+				valueFlags = append(valueFlags, "-"+f.Name)
+			}
+		}
+	}
+
+	b.WriteString("# bash completion for godsays\n")
+	b.WriteString("_godsays() {\n")
+	b.WriteString("    local cur prev cmd opts i\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	b.WriteString("    # the command is the first word that is neither a flag nor its value\n")
+	b.WriteString("    cmd=\"\"\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	fmt.Fprintf(&b, "            %s) ((i++)) ;;\n", strings.Join(valueFlags, "|"))
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) cmd=\"${COMP_WORDS[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n\n")
+	b.WriteString("    case \"$prev\" in\n")
+	fmt.Fprintf(&b, "        %s)\n", strings.Join(valueFlags, "|"))
+	b.WriteString("            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("            return ;;\n")
+	b.WriteString("    esac\n\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	fmt.Fprintf(&b, "        \"\")\n")
+	b.WriteString("            if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", bashFlags(speakCommand))
+	b.WriteString("            else\n")
+	fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(commandNames(), " "))
+	b.WriteString("            fi\n")
+	b.WriteString("            return ;;\n")
+	b.WriteString("        help)\n")
+	fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(commandNames(), " "))
+	b.WriteString("            return ;;\n")
+	b.WriteString("        completion)\n")
+	b.WriteString("            COMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\"))\n")
+	b.WriteString("            return ;;\n")
+	for _, cmd := range commands {
+		if cmd.name == "completion" {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s) opts=\"%s\" ;;\n", cmd.name, bashFlags(cmd))
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$opts\" -- \"$cur\"))\n")
+	b.WriteString("    else\n")
+	b.WriteString("        COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -F _godsays godsays\n")
+	return b.String()
+}
+
+// bashFlags returns the flags of cmd as a compgen word list
+func bashFlags(cmd *command) string {
+	var names []string
+	for _, f := range commandFlags(cmd) {
+		names = append(names, "-"+f.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// zshCompletion generates the zsh completion script
+func zshCompletion() string {
+	var b strings.Builder
+	b.WriteString("#compdef godsays\n\n")
+	b.WriteString("_godsays() {\n")
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        %s\n", zshQuote(cmd.name+":"+strings.ReplaceAll(cmd.summary, ":", `\:`)))
+	}
+	b.WriteString("        'help:Show the options of a command'\n")
+	b.WriteString("    )\n\n")
+	b.WriteString("    if (( CURRENT == 2 )); then\n")
+	b.WriteString("        _describe -t commands 'godsays command' commands\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n\n")
+	b.WriteString("    local cmd=${words[2]}\n")
+	b.WriteString("    shift words\n")
+	b.WriteString("    (( CURRENT-- ))\n")
+	b.WriteString("    case $cmd in\n")
+	b.WriteString("        help)\n")
+	b.WriteString("            _describe -t commands 'godsays command' commands ;;\n")
+	b.WriteString("        completion)\n")
+	b.WriteString("            _values 'shell' bash zsh fish ;;\n")
+	for _, cmd := range commands {
+		if cmd.name == "completion" {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n", cmd.name)
+		b.WriteString("            _arguments")
+		for _, f := range commandFlags(cmd) {
+			spec := fmt.Sprintf("-%s[%s]", f.Name, zshEscape(f.Usage))
+			switch {
+			case isBoolFlag(f):
+			case fileFlags[f.Name]:
+				spec += ":" + f.Name + ":_files"
+			default:
+				spec += ":" + f.Name + ": "
+			}
+			fmt.Fprintf(&b, " \\\n                %s", zshQuote(spec))
+		}
+		if cmd.name == "export" {
+			b.WriteString(" \\\n                '1:path:_files'")
+		}
+		b.WriteString(" ;;\n")
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	b.WriteString("if [ \"$funcstack[1]\" = \"_godsays\" ]; then\n")
+	b.WriteString("    _godsays \"$@\"\n")
+	b.WriteString("else\n")
+	b.WriteString("    compdef _godsays godsays\n")
+	b.WriteString("fi\n")
+	return b.String()
+}
+
+// zshEscape escapes the characters _arguments treats specially in
+// descriptions
+func zshEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+}
+
+// zshQuote quotes s for zsh
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishCompletion generates the fish completion script
+func fishCompletion() string {
+	var b strings.Builder
+	b.WriteString("# fish completion for godsays\n")
+	b.WriteString("complete -c godsays -f\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "complete -c godsays -n __fish_use_subcommand -a %s -d %s\n", cmd.name, fishQuote(cmd.summary))
+	}
+	b.WriteString("complete -c godsays -n __fish_use_subcommand -a help -d 'Show the options of a command'\n")
+	fmt.Fprintf(&b, "complete -c godsays -n '__fish_seen_subcommand_from help' -a %s\n", fishQuote(strings.Join(commandNames(), " ")))
+	b.WriteString("complete -c godsays -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n")
+	b.WriteString("complete -c godsays -n '__fish_seen_subcommand_from export' -F\n")
+	for _, cmd := range commands {
+		for _, f := range commandFlags(cmd) {
+			fmt.Fprintf(&b, "complete -c godsays -n '__fish_seen_subcommand_from %s' -o %s -d %s", cmd.name, f.Name, fishQuote(f.Usage))
+			switch {
+			case isBoolFlag(f):
+			case fileFlags[f.Name]:
+				b.WriteString(" -r -F")
+			default:
+				b.WriteString(" -r")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// fishQuote quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
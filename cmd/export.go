@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// DefaultExportCount is the number of messages exported by default
+const DefaultExportCount = 100
+
+// exportCommand writes a fortune(6) database of generated messages, or of
+// the wordlist itself, to the path given on the command line and its
+// strfile index to path.dat
+var exportCommand = &command{
+	name:    "export",
+	args:    "path",
+	summary: "Write a fortune(6) database to path and its strfile index to path.dat",
+	setup:   setupExport,
+}
+
+// setupExport defines the export flags
+func setupExport(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "amount", "data-dir")
+	list := fs.String("list", server.DefaultList, "Wordlist to export from: happy or NAME.txt in -data-dir")
+	count := fs.Int("count", DefaultExportCount, "Number of messages to export")
+	wordlist := fs.Bool("wordlist", false, "Export every wordlist entry as a fortune instead of generated messages")
+	var seed *int64
+	fs.Func("seed", "Seed making the exported messages reproducible", func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a 64-bit integer")
+		}
+		seed = &n
+		return nil
+	})
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			return fmt.Errorf("export needs exactly one path")
+		}
+		if *count < 1 {
+			return fmt.Errorf("count must be positive")
+		}
+		if err := g.validateAmount(); err != nil {
+			return err
+		}
+		god, err := openList(g.dataDir, *list, g.amount)
+		if err != nil {
+			return fmt.Errorf("failed to initialize God Says %w", err)
+		}
+		return export(args[0], god, g.amount, *count, seed, *wordlist)
+	}
+}
+
+// export writes count messages of god, or its wordlist, as a fortune database
+func export(path string, god *internal.God, amount, count int, seed *int64, wordlist bool) error {
+	var entries []string
+	if wordlist {
+		entries = god.Words()
+	} else {
+		// seeds draws reproducible message seeds; consecutive seeds would
+		// start with correlated words
+		var seeds *rand.Rand
+		if seed != nil {
+			seeds = rand.New(rand.NewSource(*seed))
+		}
+		entries = make([]string, count)
+		for i := range entries {
+			if seed != nil {
+				message, err := god.SpeakSeeded(amount, seeds.Int63())
+				if err != nil {
+					return err
+				}
+				entries[i] = message
+			} else {
+				entries[i] = god.Speak()
+			}
+		}
+	}
+
+	if err := writeFortunes(path, entries); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d fortunes to %s and %s.dat\n", len(entries), path, path)
+	return nil
+}
+
+// writeFortunes writes entries to the fortune database at path and its index
+func writeFortunes(path string, entries []string) error {
+	text, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer text.Close()
+	dat, err := os.Create(path + ".dat")
+	if err != nil {
+		return err
+	}
+	defer dat.Close()
+
+	textWriter, datWriter := bufio.NewWriter(text), bufio.NewWriter(dat)
+	if err := internal.WriteFortunes(textWriter, datWriter, entries); err != nil {
+		return err
+	}
+	if err := textWriter.Flush(); err != nil {
+		return err
+	}
+	if err := datWriter.Flush(); err != nil {
+		return err
+	}
+	if err := text.Close(); err != nil {
+		return err
+	}
+	return dat.Close()
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// favCommand blesses a message so it can be shown again by its ID
+var favCommand = &command{
+	name:    "fav",
+	args:    "[history-id]",
+	summary: "Bless a message recorded with -save (the newest by default) or reproduced by -seed",
+	setup:   setupFav,
+}
+
+// setupFav defines the fav flags
+func setupFav(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "amount", "data-dir", "history-file", "favorites-file")
+	list := fs.String("list", server.DefaultList, "Wordlist of the -seed message: happy or NAME.txt in -data-dir")
+	var seed *int64
+	fs.Func("seed", "Bless the message this seed reproduces with -amount and -list", func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a 64-bit integer")
+		}
+		seed = &n
+		return nil
+	})
+
+	return func(args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("unexpected argument %q", args[1])
+		}
+		if len(args) == 1 && seed != nil {
+			return fmt.Errorf("-seed cannot be combined with a history ID")
+		}
+		favorites, err := internal.OpenHistory(g.favoritesFile)
+		if err != nil {
+			return err
+		}
+
+		var entry internal.HistoryEntry
+		if seed != nil {
+			if err := g.validateAmount(); err != nil {
+				return err
+			}
+			god, err := openList(g.dataDir, *list, g.amount)
+			if err != nil {
+				return fmt.Errorf("failed to initialize God Says %w", err)
+			}
+			// blessing the same message again prints its ID
+			id := internal.SeedHistoryID(*list, g.amount, *seed)
+			if favorite, ok, err := favorites.Get(id); err != nil {
+				return err
+			} else if ok {
+				fmt.Println(favorite.ID)
+				return nil
+			}
+			words, err := god.SpeakSeededWords(g.amount, *seed)
+			if err != nil {
+				return err
+			}
+			entry = internal.HistoryEntry{ID: id, Source: "cli", Kind: "speak", GodSays: strings.Join(words, " "), Words: words, List: *list, Amount: g.amount, Seed: seed}
+		} else {
+			if entry, err = recorded(g.historyFile, args); err != nil {
+				return err
+			}
+			if favorite, ok, err := favorites.Get(entry.ID); err != nil {
+				return err
+			} else if ok {
+				fmt.Println(favorite.ID)
+				return nil
+			}
+			// the favorite keeps the recorded ID but dates from the blessing
+			entry.Time = time.Time{}
+		}
+
+		if entry, err = favorites.Add(entry); err != nil {
+			return fmt.Errorf("failed to store favorite: %w", err)
+		}
+		fmt.Println(entry.ID)
+		return nil
+	}
+}
+
+// recorded returns the history entry with the ID in args, or the newest one
+func recorded(historyFile string, args []string) (internal.HistoryEntry, error) {
+	history, err := internal.OpenHistory(historyFile)
+	if err != nil {
+		return internal.HistoryEntry{}, err
+	}
+	if len(args) == 1 {
+		entry, ok, err := history.Get(args[0])
+		if err != nil {
+			return entry, err
+		}
+		if !ok {
+			return entry, fmt.Errorf("no message %q in %s", args[0], historyFile)
+		}
+		return entry, nil
+	}
+
+	page, err := history.Query(internal.HistoryQuery{Limit: 1})
+	if err != nil {
+		return internal.HistoryEntry{}, err
+	}
+	if len(page.Entries) == 0 {
+		return internal.HistoryEntry{}, fmt.Errorf("no messages recorded in %s, speak with -save or pass -seed", historyFile)
+	}
+	return page.Entries[0], nil
+}
+
+// showCommand re-renders a blessed or recorded message
+var showCommand = &command{
+	name:    "show",
+	args:    "<id>",
+	summary: "Show a blessed or recorded message again, in any output format",
+	setup:   setupShow,
+}
+
+// setupShow defines the show flags
+func setupShow(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "history-file", "favorites-file", "no-wrap", "color")
+	var (
+		outputFormat = fs.String("output", "text", "Output format: "+strings.Join(outputFormats, ", "))
+		ascii        = fs.Bool("ascii", false, "Print the message in a cowsay style speech bubble")
+		imagePath    = fs.String("image", "", "Also render the message to an image file (.png or .svg)")
+		songPath     = fs.String("song", "", "Also sing the message into a WAV file")
+		qrPath       = fs.String("qr", "", "Also encode the message as a QR code (.png, .svg, or - to draw it in the terminal)")
+	)
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: godsays show <id>")
+		}
+		if *outputFormat != "text" && *ascii {
+			return fmt.Errorf("-output %s cannot be combined with -ascii", *outputFormat)
+		}
+		output, err := newMessageWriter(os.Stdout, *outputFormat)
+		if err != nil {
+			return err
+		}
+		terminal, err := g.terminalOptions(os.Stdout)
+		if err != nil {
+			return err
+		}
+
+		entry, err := lookup(args[0], g.favoritesFile, g.historyFile)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case *outputFormat != "text":
+			response := server.GodResponse{GodSays: entry.GodSays, Words: entry.Words, List: entry.List, Amount: entry.Amount, Seed: entry.Seed}
+			if err := output.write(response); err != nil {
+				return err
+			}
+		case *ascii:
+			art, err := internal.RenderASCII(entry.GodSays, internal.DefaultASCIIOptions())
+			if err != nil {
+				return err
+			}
+			fmt.Print(art)
+		default:
+			words := entry.Words
+			if len(words) == 0 {
+				words = strings.Fields(entry.GodSays)
+			}
+			fmt.Println(internal.FormatTerminal(words, terminal))
+		}
+
+		if *imagePath != "" {
+			if err := writeImage(*imagePath, entry.GodSays, internal.DefaultImageOptions()); err != nil {
+				return fmt.Errorf("failed to write image: %w", err)
+			}
+		}
+		if *songPath != "" {
+			if err := writeSong(*songPath, entry.GodSays, internal.DefaultSongOptions()); err != nil {
+				return fmt.Errorf("failed to write song: %w", err)
+			}
+		}
+		if *qrPath != "" {
+			if err := writeQR(*qrPath, entry.GodSays, "M", internal.DefaultQRScale, false); err != nil {
+				return fmt.Errorf("failed to write QR code: %w", err)
+			}
+		}
+		return nil
+	}
+}
+
+// lookup finds the message with id among the favorites, then the history
+func lookup(id string, paths ...string) (internal.HistoryEntry, error) {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		store, err := internal.OpenHistory(path)
+		if err != nil {
+			return internal.HistoryEntry{}, err
+		}
+		entry, ok, err := store.Get(id)
+		if err != nil {
+			return entry, err
+		}
+		if ok {
+			return entry, nil
+		}
+	}
+	return internal.HistoryEntry{}, fmt.Errorf("no favorite or recorded message %q", id)
+}
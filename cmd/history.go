@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// historyCommand prints the messages recorded with -save
+var historyCommand = &command{
+	name:    "history",
+	args:    "[-search term] [-since date]",
+	summary: "Show the messages recorded with -save, newest first",
+	setup:   setupHistory,
+}
+
+// setupHistory defines the history flags
+func setupHistory(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "tz", "history-file", "favorites-file")
+	var (
+		search = fs.String("search", "", "Only show messages or questions containing this text")
+		since  = fs.String("since", "", "Only show messages since a YYYY-MM-DD date (see -tz), an RFC 3339 time or a duration like 7d")
+		until  = fs.String("until", "", "Only show messages before a YYYY-MM-DD date, an RFC 3339 time or a duration like 7d")
+		limit  = fs.Int("limit", internal.DefaultHistoryLimit, fmt.Sprintf("Messages to show (1 - %d)", internal.MaxHistoryLimit))
+		offset = fs.Int("offset", 0, "Skip this many of the newest matching messages")
+		asJSON = fs.Bool("json", false, "Print the page of messages as JSON")
+
+		favorites = fs.Bool("favorites", false, "Show the messages blessed with fav instead")
+	)
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		loc, err := g.location()
+		if err != nil {
+			return err
+		}
+		if *limit < 1 {
+			return fmt.Errorf("-limit must be between 1 and %d", internal.MaxHistoryLimit)
+		}
+
+		query := internal.HistoryQuery{Search: *search, Offset: *offset, Limit: *limit}
+		now := time.Now()
+		if *since != "" {
+			if query.Since, err = internal.ParseSince(*since, now, loc); err != nil {
+				return err
+			}
+		}
+		if *until != "" {
+			if query.Until, err = internal.ParseSince(*until, now, loc); err != nil {
+				return err
+			}
+		}
+
+		path := g.historyFile
+		if *favorites {
+			path = g.favoritesFile
+		}
+		history, err := internal.OpenHistory(path)
+		if err != nil {
+			return err
+		}
+		page, err := history.Query(query)
+		if err != nil {
+			return err
+		}
+
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(page)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTIME\tKIND\tSEED\tMESSAGE")
+		for _, entry := range page.Entries {
+			seed := "-"
+			if entry.Seed != nil {
+				seed = strconv.FormatInt(*entry.Seed, 10)
+			}
+			message := entry.GodSays
+			if entry.Question != "" {
+				message = fmt.Sprintf("%q %s", entry.Question, entry.GodSays)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.ID, entry.Time.In(loc).Format(time.DateTime), entry.Kind, seed, message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if next := page.Offset + len(page.Entries); next < page.Total {
+			fmt.Fprintf(os.Stderr, "Showing %d of %d messages, use -offset %d for more\n", len(page.Entries), page.Total, next)
+		}
+		return nil
+	}
+}
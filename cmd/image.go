@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// writeImage renders message to path as PNG or SVG depending on its extension
+func writeImage(path, message string, opts internal.ImageOptions) error {
+	var render func(io.Writer, string, internal.ImageOptions) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		render = internal.RenderPNG
+	case ".svg":
+		render = internal.RenderSVG
+	default:
+		return fmt.Errorf("unsupported image format %q: use .png or .svg", ext)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render(f, message, opts); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxHistory is the number of lines kept in the persisted REPL history
+const MaxHistory = 1000
+
+// errInterrupted is returned when Ctrl-C aborts the line being edited
+var errInterrupted = errors.New("interrupted")
+
+// lineEditor reads lines with cursor movement and history when stdin is a
+// terminal, and plain lines otherwise
+type lineEditor struct {
+	in          *bufio.Reader
+	out         io.Writer
+	fd          int
+	terminal    bool
+	history     []string
+	historyPath string
+	// number of lines in the history file, which is rewritten once it
+	// grows past MaxHistory
+	fileLines int
+}
+
+// newLineEditor creates an editor reading stdin, loading the history file
+// at historyPath when it is not empty
+func newLineEditor(historyPath string) *lineEditor {
+	fd := int(os.Stdin.Fd())
+	e := &lineEditor{
+		in:          bufio.NewReader(os.Stdin),
+		out:         os.Stdout,
+		fd:          fd,
+		terminal:    isTerminal(fd),
+		historyPath: historyPath,
+	}
+	if historyPath != "" {
+		if data, err := os.ReadFile(historyPath); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if line != "" {
+					e.history = append(e.history, line)
+				}
+			}
+			e.fileLines = len(e.history)
+			if len(e.history) > MaxHistory {
+				e.history = e.history[len(e.history)-MaxHistory:]
+			}
+		}
+	}
+	return e
+}
+
+// historyFile returns the REPL history file in the user's config directory
+func historyFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "godsays", "repl_history"), nil
+}
+
+// addHistory records a line in memory and appends it to the history file,
+// rewriting the file with the last MaxHistory lines when it grows longer
+func (e *lineEditor) addHistory(line string) error {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return nil
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > MaxHistory {
+		e.history = e.history[len(e.history)-MaxHistory:]
+	}
+
+	if e.historyPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.historyPath), 0o700); err != nil {
+		return err
+	}
+	e.fileLines++
+	if e.fileLines > MaxHistory {
+		return e.writeHistory()
+	}
+	f, err := os.OpenFile(e.historyPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeHistory replaces the history file with the lines kept in memory
+func (e *lineEditor) writeHistory() error {
+	tmp, err := os.CreateTemp(filepath.Dir(e.historyPath), ".repl_history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(e.history, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), e.historyPath); err != nil {
+		return err
+	}
+	e.fileLines = len(e.history)
+	return nil
+}
+
+// readLine prompts for and reads one line. It returns io.EOF at the end of
+// input and errInterrupted when Ctrl-C is pressed.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	if !e.terminal {
+		line, err := e.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	state, err := makeRaw(e.fd)
+	if err != nil {
+		return "", err
+	}
+	defer restoreTerminal(e.fd, state)
+	return e.edit(prompt)
+}
+
+// edit runs the line editing loop on a terminal in raw mode
+func (e *lineEditor) edit(prompt string) (string, error) {
+	var line []rune
+	cursor := 0
+	// index into history while browsing it; len(history) is the new line
+	browse := len(e.history)
+	draft := ""
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(line))
+		if back := len(line) - cursor; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+	recall := func(i int) {
+		if browse == len(e.history) {
+			draft = string(line)
+		}
+		browse = i
+		if i == len(e.history) {
+			line = []rune(draft)
+		} else {
+			line = []rune(e.history[i])
+		}
+		cursor = len(line)
+	}
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D ends input on an empty line
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if cursor < len(line) {
+				line = append(line[:cursor], line[cursor+1:]...)
+			}
+		case 1: // Ctrl-A
+			cursor = 0
+		case 5: // Ctrl-E
+			cursor = len(line)
+		case 11: // Ctrl-K
+			line = line[:cursor]
+		case 21: // Ctrl-U
+			line = line[cursor:]
+			cursor = 0
+		case 127, 8: // Backspace
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case 27: // escape sequences of the arrow, home, end and delete keys
+			switch e.readEscape() {
+			case "[A", "OA":
+				if browse > 0 {
+					recall(browse - 1)
+				}
+			case "[B", "OB":
+				if browse < len(e.history) {
+					recall(browse + 1)
+				}
+			case "[C", "OC":
+				if cursor < len(line) {
+					cursor++
+				}
+			case "[D", "OD":
+				if cursor > 0 {
+					cursor--
+				}
+			case "[H", "OH", "[1~", "[7~":
+				cursor = 0
+			case "[F", "OF", "[4~", "[8~":
+				cursor = len(line)
+			case "[3~":
+				if cursor < len(line) {
+					line = append(line[:cursor], line[cursor+1:]...)
+				}
+			}
+		default:
+			if r >= ' ' {
+				line = append(line[:cursor], append([]rune{r}, line[cursor:]...)...)
+				cursor++
+			}
+		}
+		redraw()
+	}
+}
+
+// readEscape reads the rest of an escape sequence after ESC. A terminal
+// sends a key's whole sequence at once, so it stops at the end of the
+// buffered input instead of blocking after a lone ESC.
+func (e *lineEditor) readEscape() string {
+	var seq strings.Builder
+	for e.in.Buffered() > 0 {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return seq.String()
+		}
+		seq.WriteRune(r)
+		// CSI sequences end with a letter or '~'; SS3 ones after one letter
+		if seq.Len() > 1 && (r == '~' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+			return seq.String()
+		}
+		if seq.Len() == 1 && r != '[' && r != 'O' {
+			return seq.String()
+		}
+	}
+	return seq.String()
+}
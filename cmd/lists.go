@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// listsCommand prints the wordlists God can speak from
+var listsCommand = &command{
+	name:    "lists",
+	summary: "List the built-in wordlist and the wordlists saved in -data-dir",
+	setup:   setupLists,
+}
+
+// setupLists defines the lists flags
+func setupLists(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "data-dir")
+	asJSON := fs.Bool("json", false, "Print the wordlists as JSON")
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		lists, err := wordlists(g.dataDir)
+		if err != nil {
+			return err
+		}
+
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(lists)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tENTRIES\tUNIQUE\tUPDATED")
+		for _, list := range lists {
+			updated := "builtin"
+			if !list.Builtin {
+				updated = list.UpdatedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", list.Name, list.Stats.Entries, list.Stats.UniqueEntries, updated)
+		}
+		return w.Flush()
+	}
+}
+
+// wordlists describes the built-in wordlist and those saved in dataDir
+func wordlists(dataDir string) ([]server.WordlistInfo, error) {
+	god, err := internal.NewGod(internal.DefaultAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize God Says %w", err)
+	}
+	lists := []server.WordlistInfo{{Name: server.DefaultList, Builtin: true, Stats: god.Stats()}}
+	if dataDir == "" {
+		return lists, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dataDir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		if name == server.DefaultList {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		god, err := loadWordlist(path, internal.DefaultAmount)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping wordlist %s: %v\n", path, err)
+			continue
+		}
+		lists = append(lists, server.WordlistInfo{Name: name, UpdatedAt: info.ModTime(), Stats: god.Stats()})
+	}
+	return lists, nil
+}
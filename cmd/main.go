@@ -1,62 +1,307 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata" // Timezones for -tz on systems without zoneinfo
 
-	"github.com/omid3699/god_says/cmd/server"
 	"github.com/omid3699/god_says/internal"
 )
 
-func main() {
-	var (
-		amount = flag.Int("amount", internal.DefaultAmount, fmt.Sprintf("Number of words to generate (%d - %d)", internal.MinAmount, internal.MaxAmount))
-		help   = flag.Bool("help", false, "Show the help message")
-		http   = flag.Bool("http", false, "Start an HTTP server")
-		host   = flag.String("host", "127.0.0.1", "The HTTP server host default is 127.0.0.1")
-		port   = flag.Int("port", 3333, "The listening port of HTTP server")
-	)
-	flag.Parse()
-	if *help {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "\nGo port of Terry Davis' \"god says\" program from TempleOS\n")
-		fmt.Fprintf(os.Stderr, "Generates random words from the Happy.TXT wordlist.\n\n")
-		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
-		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  %s                    # Generate %d words (default)\n", os.Args[0], internal.DefaultAmount)
-		fmt.Fprintf(os.Stderr, "  %s -amount 10         # Generate 10 words\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -amount 100        # Generate 100 words\n", os.Args[0])
-		fmt.Fprint(os.Stderr, "\nGod Says HTTP server \n")
-		fmt.Fprintf(os.Stderr, "  %s -http                    # Start HTTP server with default host and port 127.0.0.1:3333 \n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -http -host 0.0.0.0      # Start HTTP with 0.0.0.0 as host \n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -http -port 8080         # Start HTTP server listening on port 8080 \n", os.Args[0])
-		os.Exit(0)
-	}
-
-	if !*http {
-		// Run in CLI mode
-
-		if *amount < internal.MinAmount || *amount > internal.MaxAmount {
-			fmt.Fprintf(os.Stderr, "Error: amount must be between %d and %d\n", internal.MinAmount, internal.MaxAmount)
-			os.Exit(1)
+// command is a godsays subcommand
+type command struct {
+	name string
+	// args describes the positional arguments in usage messages
+	args    string
+	summary string
+	// setup defines the command's flags on fs and returns the function
+	// running it with the remaining arguments
+	setup func(fs *flag.FlagSet, g *globals) func(args []string) error
+}
+
+// errUsage is returned for malformed command lines, which the flag
+// package has already reported together with the usage
+var errUsage = errors.New("usage error")
+
+// commands lists the subcommands in the order of the help message
+var commands []*command
+
+func init() {
+	commands = []*command{
+		speakCommand,
+		serveCommand,
+		askCommand,
+		replCommand,
+		historyCommand,
+		favCommand,
+		showCommand,
+		listsCommand,
+		exportCommand,
+		versionCommand,
+		completionCommand,
+	}
+}
+
+// findCommand returns the command called name, or nil
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
 		}
+	}
+	return nil
+}
+
+// globals holds the flags shared between commands
+type globals struct {
+	amount    int
+	tz        string
+	namespace string
+	dataDir   string
+	baseURL   string
+	noWrap    bool
+	color     string
+	// save records generated messages in historyFile
+	save        bool
+	historyFile string
+	// favoritesFile stores the messages blessed with fav
+	favoritesFile string
+}
 
-		god, err := internal.NewGod(*amount)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to initialize God Says %v \n", err)
+// defaultGlobals returns the default values of the shared flags
+func defaultGlobals() *globals {
+	g := &globals{amount: internal.DefaultAmount, tz: "UTC", namespace: internal.DefaultNamespace, color: "never"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		g.historyFile = filepath.Join(dir, "godsays", "history.jsonl")
+		g.favoritesFile = filepath.Join(dir, "godsays", "favorites.jsonl")
+	}
+	return g
+}
+
+// flags defines the named shared flags on fs, skipping ones already defined
+// so commands can be combined on one flag set. Defaults are the current
+// values, so flags given before a subcommand carry over.
+func (g *globals) flags(fs *flag.FlagSet, names ...string) {
+	for _, name := range names {
+		if fs.Lookup(name) != nil {
+			continue
+		}
+		switch name {
+		case "amount":
+			fs.IntVar(&g.amount, name, g.amount, fmt.Sprintf("Number of words to generate (%d - %d)", internal.MinAmount, internal.MaxAmount))
+		case "tz":
+			fs.StringVar(&g.tz, name, g.tz, "IANA timezone deciding the date of the message of the day, e.g. Europe/Berlin or Local")
+		case "namespace":
+			fs.StringVar(&g.namespace, name, g.namespace, "Namespace of the message of the day; teams sharing it see the same message")
+		case "data-dir":
+			fs.StringVar(&g.dataDir, name, g.dataDir, "Directory of saved wordlists, uploaded through the server's admin API")
+		case "base-url":
+			fs.StringVar(&g.baseURL, name, g.baseURL, "Public URL of the server used in feed links and QR permalinks, e.g. https://godsays.example.com")
+		case "no-wrap":
+			fs.BoolVar(&g.noWrap, name, g.noWrap, "Print messages on one line instead of wrapping them at the terminal width")
+		case "color":
+			fs.StringVar(&g.color, name, g.color, "Color words in the TempleOS palette: never, always or auto (on terminals unless $NO_COLOR is set)")
+		case "save":
+			fs.BoolVar(&g.save, name, g.save, "Record generated messages in -history-file")
+		case "history-file":
+			fs.StringVar(&g.historyFile, name, g.historyFile, "File generated messages are recorded in with -save and read from by the history command")
+		case "favorites-file":
+			fs.StringVar(&g.favoritesFile, name, g.favoritesFile, "File blessed messages are stored in")
+		default:
+			panic("unknown shared flag " + name)
 		}
+	}
+}
+
+// location loads the -tz timezone
+func (g *globals) location() (*time.Location, error) {
+	loc, err := time.LoadLocation(g.tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", g.tz, err)
+	}
+	return loc, nil
+}
+
+// validateAmount checks the -amount flag
+func (g *globals) validateAmount() error {
+	if g.amount < internal.MinAmount || g.amount > internal.MaxAmount {
+		return fmt.Errorf("amount must be between %d and %d", internal.MinAmount, internal.MaxAmount)
+	}
+	return nil
+}
+
+// history opens the message history when -save is set, and returns nil
+// otherwise
+func (g *globals) history() (*internal.History, error) {
+	if !g.save {
+		return nil, nil
+	}
+	if g.historyFile == "" {
+		return nil, fmt.Errorf("-save requires -history-file")
+	}
+	return internal.OpenHistory(g.historyFile)
+}
+
+// record saves entry in history, if any, warning when that fails
+func record(history *internal.History, entry internal.HistoryEntry) {
+	if history == nil {
+		return
+	}
+	if _, err := history.Add(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record message in history: %v\n", err)
+	}
+}
 
-		message := god.Speak()
-		fmt.Println(message)
-	} else {
-		// Run in in HTTP server mode
-		log.Printf("Starting God Says HTTP server host: %s port: %d", *host, *port)
-		err := server.RunServer(*host, *port)
-		if err != nil {
-			log.Fatalf("Error in running God Says HTTP server: %s", err)
+// terminalOptions decides how messages printed to f are wrapped and
+// colored. Only terminals are wrapped, so pipes get one message per line.
+func (g *globals) terminalOptions(f *os.File) (internal.TerminalOptions, error) {
+	width := terminalWidth(int(f.Fd()))
+	opts := internal.TerminalOptions{}
+	if !g.noWrap {
+		opts.Width = width
+	}
+
+	switch g.color {
+	case "always":
+		opts.Color = true
+	case "never":
+	case "auto":
+		opts.Color = width > 0 && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	default:
+		return opts, fmt.Errorf("color must be auto, always or never")
+	}
+	return opts, nil
+}
+
+// newFlagSet creates the flag set of cmd with its usage message
+func newFlagSet(cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [options] %s\n\n", os.Args[0], cmd.name, cmd.args)
+		fmt.Fprintf(os.Stderr, "%s\n\nOptions:\n", cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// runCommand parses args for cmd and runs it
+func runCommand(cmd *command, g *globals, args []string) error {
+	fs := newFlagSet(cmd)
+	run := cmd.setup(fs, g)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	return run(fs.Args())
+}
+
+// parseFlags parses args, turning errors the flag package reported into
+// errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errUsage
+	}
+	return err
+}
+
+// runLegacy runs the flat command line of earlier versions, where speak and
+// serve flags share one flag set, -http switches to the server and ask,
+// repl and export follow the flags
+func runLegacy(args []string) error {
+	g := defaultGlobals()
+	fs := flag.NewFlagSet("godsays", flag.ContinueOnError)
+	fs.Usage = usage
+	help := fs.Bool("help", false, "Show the help message")
+	http := fs.Bool("http", false, "Start an HTTP server (deprecated, use the serve command)")
+	speak := speakCommand.setup(fs, g)
+	serve := serveCommand.setup(fs, g)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch {
+	case *help:
+		usage()
+		return nil
+	case *http:
+		fmt.Fprintf(os.Stderr, "Warning: -http is deprecated, use \"%s serve\" instead\n", os.Args[0])
+		return serve(fs.Args())
+	case fs.NArg() > 0:
+		cmd := findCommand(fs.Arg(0))
+		if cmd == nil {
+			return fmt.Errorf("unknown command %q, see %s help", fs.Arg(0), os.Args[0])
 		}
+		return runCommand(cmd, g, fs.Args()[1:])
+	default:
+		return speak(nil)
+	}
+}
+
+// usage prints the overview of every command
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options] [arguments]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\nGo port of Terry Davis' \"god says\" program from TempleOS\n")
+	fmt.Fprintf(os.Stderr, "Generates random words from the Happy.TXT wordlist.\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"%s help <command>\" for the options of a command.\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Without a command %s speaks; flags of earlier versions such as -http still work.\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  %s                          # Generate %d words\n", os.Args[0], internal.DefaultAmount)
+	fmt.Fprintf(os.Stderr, "  %s speak -amount 10         # Generate 10 words\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s speak -today -tz Europe/Berlin -namespace team  # Team message of the day\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s ask \"Why?\"               # Ask God a question\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s serve -port 8080         # Start the HTTP server on port 8080\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s completion bash          # Print the bash completion script\n", os.Args[0])
+}
+
+// runHelp prints the usage of a command, or the overview
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return nil
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	fs := newFlagSet(cmd)
+	cmd.setup(fs, defaultGlobals())
+	fs.Usage()
+	return nil
+}
+
+func main() {
+	args := os.Args[1:]
+
+	var err error
+	switch {
+	case len(args) > 0 && args[0] == "help":
+		err = runHelp(args[1:])
+	case len(args) > 0 && !strings.HasPrefix(args[0], "-"):
+		cmd := findCommand(args[0])
+		if cmd == nil {
+			err = fmt.Errorf("unknown command %q, see %s help", args[0], os.Args[0])
+			break
+		}
+		err = runCommand(cmd, defaultGlobals(), args[1:])
+	default:
+		err = runLegacy(args)
+	}
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 }
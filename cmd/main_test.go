@@ -1,10 +1,23 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/omid3699/god_says/internal"
 )
 
 func TestCLIBasicUsage(t *testing.T) {
@@ -84,3 +97,811 @@ func TestCLIHelp(t *testing.T) {
 		t.Errorf("Expected help output to contain 'Usage:', got: %s", outputStr)
 	}
 }
+
+func TestCLIAsk(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	first, err := exec.Command("./godsays-test", "ask", "Will it rain?").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	second, err := exec.Command("./godsays-test", "ask", "will", "it", "RAIN").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+
+	if len(strings.TrimSpace(string(first))) == 0 {
+		t.Error("Expected non-empty answer")
+	}
+	if string(first) != string(second) {
+		t.Errorf("Expected the same answer for the same question, got %q and %q", first, second)
+	}
+
+	if err := exec.Command("./godsays-test", "ask").Run(); err == nil {
+		t.Error("Expected error for empty question, got none")
+	}
+}
+
+func TestCLIToday(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	first, err := exec.Command("./godsays-test", "-today", "-tz", "UTC", "-namespace", "team").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	second, err := exec.Command("./godsays-test", "-today", "-tz", "UTC", "-namespace", "team").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if len(strings.TrimSpace(string(first))) == 0 || string(first) != string(second) {
+		t.Errorf("Expected a stable message of the day, got %q and %q", first, second)
+	}
+
+	if err := exec.Command("./godsays-test", "-today", "-tz", "Nowhere/Special").Run(); err == nil {
+		t.Error("Expected error for invalid timezone, got none")
+	}
+}
+
+func TestCLIImage(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	for _, name := range []string{"god.png", "god.svg"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := exec.Command("./godsays-test", "-amount", "5", "-image", path).Run(); err != nil {
+			t.Fatalf("CLI execution failed for %s: %v", name, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Expected image %s to be written: %v", name, err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("Expected non-empty image %s", name)
+		}
+	}
+
+	if err := exec.Command("./godsays-test", "-image", filepath.Join(t.TempDir(), "god.gif")).Run(); err == nil {
+		t.Error("Expected error for unsupported image format, got none")
+	}
+}
+
+func TestCLISong(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	path := filepath.Join(t.TempDir(), "god.wav")
+	if err := exec.Command("./godsays-test", "-amount", "5", "-song", path, "-song-tempo", "180").Run(); err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected song to be written: %v", err)
+	}
+	if len(data) <= 44 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		t.Errorf("Expected a WAV file, got %d bytes", len(data))
+	}
+
+	if err := exec.Command("./godsays-test", "-song", path, "-song-tempo", "1").Run(); err == nil {
+		t.Error("Expected error for invalid tempo, got none")
+	}
+}
+
+func TestCLIASCII(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	output, err := exec.Command("./godsays-test", "-amount", "5", "-ascii", "-ascii-character", "cow", "-ascii-border", "plain").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if !strings.HasPrefix(string(output), " _") || !strings.Contains(string(output), "(oo)") {
+		t.Errorf("Expected a cow with a plain bubble, got:\n%s", output)
+	}
+
+	if err := exec.Command("./godsays-test", "-ascii", "-ascii-banner").Run(); err == nil {
+		t.Error("Expected error for a banner of 32 words, got none")
+	}
+}
+
+func TestCLIQR(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	for _, name := range []string{"god.png", "god.svg"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := exec.Command("./godsays-test", "-amount", "5", "-qr", path, "-qr-level", "H").Run(); err != nil {
+			t.Fatalf("CLI execution failed for %s: %v", name, err)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Expected QR code %s to be written: %v", name, err)
+		}
+	}
+
+	output, err := exec.Command("./godsays-test", "-amount", "5", "-qr", "-", "-qr-link", "-base-url", "https://godsays.example.com").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if !strings.Contains(string(output), "▄") {
+		t.Errorf("Expected a QR code drawn in the terminal, got:\n%s", output)
+	}
+
+	if err := exec.Command("./godsays-test", "-qr", "-", "-qr-link").Run(); err == nil {
+		t.Error("Expected error for -qr-link without -base-url, got none")
+	}
+	if err := exec.Command("./godsays-test", "-qr", "-", "-qr-level", "X").Run(); err == nil {
+		t.Error("Expected error for an unknown error correction level, got none")
+	}
+}
+
+func TestCLIFortune(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	output, err := exec.Command("./godsays-test", "-fortune", "-s", "-n", "50").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if fortune := strings.TrimSpace(string(output)); len(fortune) == 0 || len(fortune) > 50 {
+		t.Errorf("Expected a short fortune of at most 50 characters, got %q", fortune)
+	}
+
+	if err := exec.Command("./godsays-test", "-fortune", "-s", "-l").Run(); err == nil {
+		t.Error("Expected error for -s with -l, got none")
+	}
+}
+
+func TestCLIExport(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	path := filepath.Join(t.TempDir(), "godsays")
+	if err := exec.Command("./godsays-test", "-amount", "5", "export", "-count", "10", "-seed", "42", path).Run(); err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the fortune database to be written: %v", err)
+	}
+	if strings.Count(string(text), "\n%\n") != 10 {
+		t.Errorf("Expected 10 fortunes, got:\n%s", text)
+	}
+	if info, err := os.Stat(path + ".dat"); err != nil || info.Size() != 24+11*4 {
+		t.Errorf("Expected a strfile index with 11 offsets: %v", err)
+	}
+
+	again := filepath.Join(t.TempDir(), "again")
+	if err := exec.Command("./godsays-test", "-amount", "5", "export", "-count", "10", "-seed", "42", again).Run(); err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if second, _ := os.ReadFile(again); string(second) != string(text) {
+		t.Error("Expected seeded exports to be reproducible")
+	}
+
+	if err := exec.Command("./godsays-test", "export", "-wordlist", path).Run(); err != nil {
+		t.Errorf("Failed to export the wordlist: %v", err)
+	}
+	if err := exec.Command("./godsays-test", "export").Run(); err == nil {
+		t.Error("Expected error without a path, got none")
+	}
+
+	// saved wordlists are exported like speak uses them
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "team.txt"), []byte("ship it\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("./godsays-test", "export", "-data-dir", dataDir, "-list", "team", "-wordlist", path).Run(); err != nil {
+		t.Fatalf("Failed to export a saved wordlist: %v", err)
+	}
+	if text, _ := os.ReadFile(path); string(text) != "ship it\n%\n" {
+		t.Errorf("Expected the team wordlist, got %q", text)
+	}
+	if err := exec.Command("./godsays-test", "export", "-list", "missing", path).Run(); err == nil {
+		t.Error("Expected error for an unknown list, got none")
+	}
+}
+
+func TestCLIREPL(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	history := filepath.Join(t.TempDir(), "godsays", "repl_history")
+	run := func(input string) string {
+		t.Helper()
+		cmd := exec.Command("./godsays-test", "repl", "-history", history)
+		cmd.Stdin = strings.NewReader(input)
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("CLI execution failed: %v", err)
+		}
+		return string(output)
+	}
+
+	input := ":amount 3\n:seed 42\n\n:format json\nWhy?\n:list team\n:bogus\n:quit\n"
+	output := run(input)
+	if output != run(input) {
+		t.Error("Expected a seeded session to replay identically")
+	}
+	for _, expected := range []string{`"question":"Why?"`, `"amount":3`, "Error: unknown wordlist", "Error: unknown command :bogus"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	data, err := os.ReadFile(history)
+	if err != nil {
+		t.Fatalf("Expected history to be persisted: %v", err)
+	}
+	if !strings.HasPrefix(string(data), ":amount 3\n:seed 42\n:format json\nWhy?\n") {
+		t.Errorf("Unexpected history:\n%s", data)
+	}
+
+	// the history carries over into the next session
+	if output := run(":history\n"); !strings.Contains(output, "1  :amount 3") {
+		t.Errorf("Expected the previous session in :history, got:\n%s", output)
+	}
+
+	// the file is cut back to the last MaxHistory lines
+	var long strings.Builder
+	for i := 0; i < MaxHistory+5; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	if err := os.WriteFile(history, []byte(long.String()), 0o600); err != nil {
+		t.Fatalf("Failed to write history: %v", err)
+	}
+	run("latest\n")
+	data, err = os.ReadFile(history)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != MaxHistory || lines[0] != "line 6" || lines[len(lines)-1] != "latest" {
+		t.Errorf("Expected the last %d lines in the history file, got %d from %q to %q", MaxHistory, len(lines), lines[0], lines[len(lines)-1])
+	}
+}
+
+func TestCLISubcommands(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	output, err := exec.Command("./godsays-test", "speak", "-amount", "3").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		t.Error("Expected speak to print a message")
+	}
+
+	output, _ = exec.Command("./godsays-test", "help", "speak").CombinedOutput()
+	for _, expected := range []string{"Usage:", "speak", "-amount", "-today"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected speak help to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(string(output), "-port") {
+		t.Errorf("Expected speak help without server flags, got:\n%s", output)
+	}
+
+	output, err = exec.Command("./godsays-test", "version", "-json").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if !strings.Contains(string(output), `"go_version"`) {
+		t.Errorf("Expected build information, got:\n%s", output)
+	}
+
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "team.txt"), []byte("ship it\nrollback\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	output, err = exec.Command("./godsays-test", "lists", "-data-dir", dataDir).Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	for _, expected := range []string{"happy", "team"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected lists to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if err := exec.Command("./godsays-test", "bogus").Run(); err == nil {
+		t.Error("Expected error for an unknown command, got none")
+	}
+	if err := exec.Command("./godsays-test", "speak", "-port", "80").Run(); err == nil {
+		t.Error("Expected error for a server flag on speak, got none")
+	}
+}
+
+func TestCLILegacyHTTP(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	// an invalid host makes the server fail right after the warning
+	output, err := exec.Command("./godsays-test", "-http", "-host", "256.0.0.1", "-port", "1").CombinedOutput()
+	if err == nil {
+		t.Error("Expected the server to fail on an invalid host")
+	}
+	if !strings.Contains(string(output), "-http is deprecated") {
+		t.Errorf("Expected a deprecation warning, got:\n%s", output)
+	}
+}
+
+func TestCLICompletion(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	expected := map[string][]string{
+		"bash": {"complete -F _godsays godsays", "serve", "-cors-origins"},
+		"zsh":  {"#compdef godsays", "serve:Start the HTTP server", "'-today[", "compdef _godsays godsays"},
+		"fish": {"complete -c godsays", "-a serve", "-o cors-origins"},
+	}
+	for shell, contents := range expected {
+		output, err := exec.Command("./godsays-test", "completion", shell).Output()
+		if err != nil {
+			t.Fatalf("CLI execution failed for %s: %v", shell, err)
+		}
+		for _, content := range contents {
+			if !strings.Contains(string(output), content) {
+				t.Errorf("Expected %s completion to contain %q", shell, content)
+			}
+		}
+	}
+
+	if path, err := exec.LookPath("bash"); err == nil {
+		script, _ := exec.Command("./godsays-test", "completion", "bash").Output()
+		check := exec.Command(path, "-n")
+		check.Stdin = strings.NewReader(string(script))
+		if output, err := check.CombinedOutput(); err != nil {
+			t.Errorf("Expected a valid bash script: %v\n%s", err, output)
+		}
+	}
+
+	if err := exec.Command("./godsays-test", "completion", "powershell").Run(); err == nil {
+		t.Error("Expected error for an unsupported shell, got none")
+	}
+}
+
+func TestCLIRemote(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	var mu sync.Mutex
+	var queries []string
+	var conns atomic.Int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+		if r.URL.Query().Get("list") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unknown_list", "message": "unknown wordlist"})
+			return
+		}
+		seed := int64(42)
+		json.NewEncoder(w).Encode(map[string]any{"god_says": "shared team words", "list": r.URL.Query().Get("list"), "amount": 3, "seed": seed})
+	}))
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(ca, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := exec.Command("./godsays-test", "speak", "-remote", ts.URL, "-remote-token", "s3cret", "-remote-ca", ca,
+		"-amount", "3", "-seed", "42", "-list", "team").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if strings.TrimSpace(string(output)) != "shared team words" {
+		t.Errorf("Expected the server's message, got %q", output)
+	}
+	mu.Lock()
+	if len(queries) != 1 || queries[0] != "/json?amount=3&list=team&seed=42" {
+		t.Errorf("Expected amount, list and seed to be forwarded, got %v", queries)
+	}
+	mu.Unlock()
+
+	// the test certificate is not trusted without -remote-ca
+	if err := exec.Command("./godsays-test", "speak", "-remote", ts.URL, "-remote-token", "s3cret", "-remote-fallback=false").Run(); err == nil {
+		t.Error("Expected error for an untrusted certificate, got none")
+	}
+	if err := exec.Command("./godsays-test", "speak", "-remote", ts.URL, "-remote-token", "s3cret", "-remote-insecure", "-list", "missing").Run(); err == nil {
+		t.Error("Expected error for an unknown remote wordlist, got none")
+	}
+
+	// a watch reuses one connection for all its messages
+	before := conns.Load()
+	output, err = exec.Command("./godsays-test", "speak", "-remote", ts.URL, "-remote-token", "s3cret", "-remote-ca", ca,
+		"-every", "10ms", "-count", "3").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if n := strings.Count(string(output), "shared team words"); n != 3 {
+		t.Errorf("Expected 3 remote messages, got %d:\n%s", n, output)
+	}
+	if n := conns.Load() - before; n != 1 {
+		t.Errorf("Expected the watch to use 1 connection, got %d", n)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	output, err = exec.Command("./godsays-test", "speak", "-remote", closed.URL, "-amount", "3").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Expected a local fallback, got %v:\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "generating locally") {
+		t.Errorf("Expected a fallback warning, got:\n%s", output)
+	}
+	if err := exec.Command("./godsays-test", "speak", "-remote", closed.URL, "-remote-fallback=false").Run(); err == nil {
+		t.Error("Expected error without fallback, got none")
+	}
+}
+
+func TestCLIWatch(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	output, err := exec.Command("./godsays-test", "speak", "-amount", "2", "-seed", "7", "-every", "10ms", "-count", "3").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 messages, got %d:\n%s", len(lines), output)
+	}
+	if lines[0] == lines[1] && lines[1] == lines[2] {
+		t.Error("Expected a new message on every interval")
+	}
+	again, _ := exec.Command("./godsays-test", "speak", "-amount", "2", "-seed", "7", "-every", "10ms", "-count", "3").Output()
+	if string(again) != string(output) {
+		t.Error("Expected seeded watches to be reproducible")
+	}
+
+	output, err = exec.Command("./godsays-test", "speak", "-every", "10ms", "-count", "2", "-overwrite").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if strings.Count(string(output), "\r\x1b[K") != 2 || strings.Count(string(output), "\n") != 1 {
+		t.Errorf("Expected two overwritten lines, got %q", output)
+	}
+
+	// SIGINT ends an endless watch cleanly
+	cmd = exec.Command("./godsays-test", "speak", "-every", "20ms")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	// the first message shows the signal handler is installed
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatalf("Expected a message before the interrupt: %v", err)
+	}
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Expected a clean exit on SIGINT, got %v", err)
+	}
+
+	for _, args := range [][]string{{"-count", "3"}, {"-every", "-1s"}, {"-every", "1s", "-clear", "-overwrite"}} {
+		if err := exec.Command("./godsays-test", append([]string{"speak"}, args...)...).Run(); err == nil {
+			t.Errorf("Expected error for %v, got none", args)
+		}
+	}
+}
+
+func TestCLIOutputFormats(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	run := func(args ...string) string {
+		t.Helper()
+		output, err := exec.Command("./godsays-test", append([]string{"speak", "-amount", "3", "-seed", "42"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("CLI execution failed for %v: %v", args, err)
+		}
+		return string(output)
+	}
+
+	var response struct {
+		GodSays string   `json:"god_says"`
+		Words   []string `json:"words"`
+		List    string   `json:"list"`
+		Amount  int      `json:"amount"`
+		Seed    *int64   `json:"seed"`
+	}
+	if err := json.Unmarshal([]byte(run("-output", "json")), &response); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(response.Words) != 3 || response.List != "happy" || response.Amount != 3 || response.Seed == nil || *response.Seed != 42 {
+		t.Errorf("Unexpected JSON output: %+v", response)
+	}
+	text := strings.TrimSpace(run())
+	if response.GodSays != text {
+		t.Errorf("Expected the text message %q in JSON, got %q", text, response.GodSays)
+	}
+
+	lines := strings.Split(strings.TrimSpace(run("-output", "ndjson", "-every", "1ms", "-count", "3")), "\n")
+	if len(lines) != 3 {
+		t.Errorf("Expected one JSON document per line, got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	yaml := run("-output", "yaml")
+	for _, expected := range []string{"god_says: ", "words:\n  - ", "list: \"happy\"\n", "amount: 3\n", "seed: 42\n"} {
+		if !strings.Contains(yaml, expected) {
+			t.Errorf("Expected YAML output to contain %q, got:\n%s", expected, yaml)
+		}
+	}
+
+	csvOutput := run("-output", "csv", "-every", "1ms", "-count", "2")
+	if !strings.HasPrefix(csvOutput, "god_says,words,list,amount,seed\n") || strings.Count(csvOutput, ",happy,3,") != 2 {
+		t.Errorf("Unexpected CSV output:\n%s", csvOutput)
+	}
+
+	for _, args := range [][]string{{"-output", "xml"}, {"-output", "json", "-ascii"}} {
+		if err := exec.Command("./godsays-test", append([]string{"speak"}, args...)...).Run(); err == nil {
+			t.Errorf("Expected error for %v, got none", args)
+		}
+	}
+}
+
+func TestCLIColor(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	// pipes are neither wrapped nor colored by default
+	plain, err := exec.Command("./godsays-test", "speak", "-amount", "200", "-seed", "1").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if strings.Contains(string(plain), "\x1b[") || strings.Count(string(plain), "\n") != 1 {
+		t.Errorf("Expected a single uncolored line, got %q", plain)
+	}
+
+	colored, err := exec.Command("./godsays-test", "speak", "-amount", "3", "-seed", "1", "-color", "always").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if strings.Count(string(colored), "\x1b[0m") != 3 {
+		t.Errorf("Expected 3 colored words, got %q", colored)
+	}
+
+	// auto only colors terminals
+	auto, err := exec.Command("./godsays-test", "speak", "-amount", "3", "-seed", "1", "-color", "auto").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if strings.Contains(string(auto), "\x1b[") {
+		t.Errorf("Expected no colors on a pipe with -color auto, got %q", auto)
+	}
+
+	if err := exec.Command("./godsays-test", "speak", "-color", "sometimes").Run(); err == nil {
+		t.Error("Expected error for an invalid -color, got none")
+	}
+}
+
+func TestCLIHistory(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+
+	// messages are only recorded with -save
+	if err := exec.Command("./godsays-test", "speak", "-history-file", historyFile).Run(); err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if _, err := os.Stat(historyFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no history file without -save, got %v", err)
+	}
+
+	spoken, err := exec.Command("./godsays-test", "speak", "-save", "-history-file", historyFile, "-seed", "42").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	answer, err := exec.Command("./godsays-test", "ask", "-save", "-history-file", historyFile, "Will it rain?").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+
+	output, err := exec.Command("./godsays-test", "history", "-history-file", historyFile, "-json").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	var page internal.HistoryPage
+	if err := json.Unmarshal(output, &page); err != nil {
+		t.Fatalf("Failed to parse history: %v", err)
+	}
+	if page.Total != 2 {
+		t.Fatalf("Expected 2 recorded messages, got %+v", page)
+	}
+	if page.Entries[0].Kind != "ask" || page.Entries[0].Question != "Will it rain?" || page.Entries[0].GodSays != strings.TrimSpace(string(answer)) {
+		t.Errorf("Expected the answer first, got %+v", page.Entries[0])
+	}
+	if page.Entries[1].GodSays != strings.TrimSpace(string(spoken)) || page.Entries[1].Seed == nil || *page.Entries[1].Seed != 42 {
+		t.Errorf("Expected the seeded message with its seed, got %+v", page.Entries[1])
+	}
+
+	output, err = exec.Command("./godsays-test", "history", "-history-file", historyFile, "--search", "RAIN", "--since", "1d").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(output)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "Will it rain?") {
+		t.Errorf("Expected a header and the answer, got %q", output)
+	}
+
+	if err := exec.Command("./godsays-test", "history", "-history-file", historyFile, "-since", "yesterday").Run(); err == nil {
+		t.Error("Expected error for an invalid -since, got none")
+	}
+}
+
+func TestCLIFavorites(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "godsays-test", ".")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to build CLI: %v", err)
+	}
+	defer os.Remove("godsays-test")
+
+	dir := t.TempDir()
+	files := []string{"-history-file", filepath.Join(dir, "history.jsonl"), "-favorites-file", filepath.Join(dir, "favorites.jsonl")}
+	// flags must come before the ID
+	run := func(command string, args ...string) (string, error) {
+		args = append(append([]string{command}, files...), args...)
+		output, err := exec.Command("./godsays-test", args...).Output()
+		return strings.TrimSpace(string(output)), err
+	}
+
+	if _, err := run("fav"); err == nil {
+		t.Error("Expected error blessing an empty history, got none")
+	}
+
+	spoken, err := exec.Command("./godsays-test", "speak", "-save", "-history-file", files[1], "-seed", "7").Output()
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	id, err := run("fav")
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if len(id) != 8 {
+		t.Fatalf("Expected an 8 character ID, got %q", id)
+	}
+	if again, _ := run("fav", id); again != id {
+		t.Errorf("Expected blessing again to keep ID %q, got %q", id, again)
+	}
+
+	shown, err := run("show", id)
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if shown != strings.TrimSpace(string(spoken)) {
+		t.Errorf("Expected %q, got %q", spoken, shown)
+	}
+
+	seeded, err := run("fav", "-seed", "7", "-amount", "5")
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if again, _ := run("fav", "-seed", "7", "-amount", "5"); again != seeded {
+		t.Errorf("Expected blessing the seed again to keep ID %q, got %q", seeded, again)
+	}
+	output, err := run("show", "-output", "json", seeded)
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	var response struct {
+		Words []string `json:"words"`
+		Seed  *int64   `json:"seed"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+	if len(response.Words) != 5 || response.Seed == nil || *response.Seed != 7 {
+		t.Errorf("Expected 5 words seeded with 7, got %+v", response)
+	}
+
+	listed, err := run("history", "-favorites")
+	if err != nil {
+		t.Fatalf("CLI execution failed: %v", err)
+	}
+	if !strings.Contains(listed, id) || !strings.Contains(listed, seeded) {
+		t.Errorf("Expected both favorites listed, got %q", listed)
+	}
+
+	if _, err := run("show", "missing"); err == nil {
+		t.Error("Expected error for an unknown ID, got none")
+	}
+
+	// serving favorites without an admin token warns before the invalid
+	// host makes the server fail
+	for token, warns := range map[string]bool{"": true, "secret": false} {
+		output, _ := exec.Command("./godsays-test", "serve", "-favorites", "-favorites-file", files[3], "-admin-token", token, "-host", "256.0.0.1", "-port", "1").CombinedOutput()
+		if strings.Contains(string(output), "anyone can bless messages") != warns {
+			t.Errorf("Expected a warning about open favorites only without a token, got with %q:\n%s", token, output)
+		}
+	}
+}
+
+func TestLineEditorEscape(t *testing.T) {
+	// a lone ESC with nothing else pending must not wait for more input
+	r, w := io.Pipe()
+	defer w.Close()
+	e := &lineEditor{in: bufio.NewReader(r)}
+	go w.Write([]byte{27})
+	if key, _, _ := e.in.ReadRune(); key != 27 {
+		t.Fatalf("Expected ESC, got %q", key)
+	}
+	if seq := e.readEscape(); seq != "" {
+		t.Errorf("Expected an empty sequence for a lone ESC, got %q", seq)
+	}
+
+	e = &lineEditor{in: bufio.NewReader(strings.NewReader("\x1b[Ax"))}
+	e.in.ReadRune()
+	if seq := e.readEscape(); seq != "[A" {
+		t.Errorf("Expected [A, got %q", seq)
+	}
+}
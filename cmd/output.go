@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/omid3699/god_says/cmd/server"
+)
+
+// outputFormats are the values accepted by -output
+var outputFormats = []string{"text", "json", "ndjson", "yaml", "csv"}
+
+// csvWordSeparator joins the words of a message in a single CSV field
+const csvWordSeparator = "|"
+
+// messageWriter prints messages with the metadata of the server's JSON
+// responses. Several messages, as printed by -every, form a stream: JSON
+// documents follow each other, YAML documents are separated by ---, and
+// CSV has a single header row.
+type messageWriter struct {
+	w      io.Writer
+	format string
+	csv    *csv.Writer
+	// written counts the messages printed so far
+	written int
+}
+
+// newMessageWriter creates a writer for one of outputFormats
+func newMessageWriter(w io.Writer, format string) (*messageWriter, error) {
+	mw := &messageWriter{w: w, format: format}
+	switch format {
+	case "text", "json", "ndjson", "yaml":
+	case "csv":
+		mw.csv = csv.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q: use %s", format, strings.Join(outputFormats, ", "))
+	}
+	return mw, nil
+}
+
+// write prints one message
+func (mw *messageWriter) write(r server.GodResponse) error {
+	defer func() { mw.written++ }()
+	if r.Words == nil {
+		r.Words = []string{}
+	}
+
+	switch mw.format {
+	case "json":
+		encoder := json.NewEncoder(mw.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case "ndjson":
+		return json.NewEncoder(mw.w).Encode(r)
+	case "yaml":
+		return mw.writeYAML(r)
+	case "csv":
+		return mw.writeCSV(r)
+	default:
+		_, err := fmt.Fprintln(mw.w, r.GodSays)
+		return err
+	}
+}
+
+// writeYAML prints r as a YAML document. Strings are written as JSON
+// strings, which are valid double quoted YAML scalars.
+func (mw *messageWriter) writeYAML(r server.GodResponse) error {
+	var b strings.Builder
+	if mw.written > 0 {
+		b.WriteString("---\n")
+	}
+	fmt.Fprintf(&b, "god_says: %s\n", yamlString(r.GodSays))
+	if len(r.Words) == 0 {
+		b.WriteString("words: []\n")
+	} else {
+		b.WriteString("words:\n")
+		for _, word := range r.Words {
+			fmt.Fprintf(&b, "  - %s\n", yamlString(word))
+		}
+	}
+	fmt.Fprintf(&b, "list: %s\n", yamlString(r.List))
+	fmt.Fprintf(&b, "amount: %d\n", r.Amount)
+	if r.Seed != nil {
+		fmt.Fprintf(&b, "seed: %d\n", *r.Seed)
+	}
+	_, err := io.WriteString(mw.w, b.String())
+	return err
+}
+
+// yamlString quotes s as a double quoted YAML scalar
+func yamlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// writeCSV prints r as a CSV row, preceded by the header for the first one
+func (mw *messageWriter) writeCSV(r server.GodResponse) error {
+	if mw.written == 0 {
+		if err := mw.csv.Write([]string{"god_says", "words", "list", "amount", "seed"}); err != nil {
+			return err
+		}
+	}
+	seed := ""
+	if r.Seed != nil {
+		seed = strconv.FormatInt(*r.Seed, 10)
+	}
+	record := []string{r.GodSays, strings.Join(r.Words, csvWordSeparator), r.List, strconv.Itoa(r.Amount), seed}
+	if err := mw.csv.Write(record); err != nil {
+		return err
+	}
+	mw.csv.Flush()
+	return mw.csv.Error()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// writeQR encodes data as a QR code and writes it to path as PNG or SVG
+// depending on its extension, or draws it on stdout when path is "-"
+func writeQR(path, data, levelName string, scale int, invert bool) error {
+	level, err := internal.ParseQRLevel(levelName)
+	if err != nil {
+		return err
+	}
+	q, err := internal.EncodeQR([]byte(data), level)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return q.WriteTerminal(os.Stdout, invert)
+	}
+
+	var render func(*os.File) error
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		render = func(f *os.File) error { return q.WritePNG(f, scale) }
+	case ".svg":
+		render = func(f *os.File) error { return q.WriteSVG(f, scale) }
+	default:
+		return fmt.Errorf("unsupported QR code format %q: use .png, .svg or -", ext)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render(f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// seedPermalink links to the seeded message on a server at base
+func seedPermalink(base string, seed int64, amount int, list string) string {
+	query := url.Values{}
+	query.Set("amount", strconv.Itoa(amount))
+	query.Set("seed", strconv.FormatInt(seed, 10))
+	if list != server.DefaultList {
+		query.Set("list", list)
+	}
+	return strings.TrimSuffix(base, "/") + "/?" + query.Encode()
+}
+
+// dayPermalink links to the message of the day of date on a server at base
+func dayPermalink(base string, date time.Time, amount int, list string) string {
+	link := fmt.Sprintf("%s/day/%s?amount=%d", strings.TrimSuffix(base, "/"), date.Format(internal.DateSaltLayout), amount)
+	if list != server.DefaultList {
+		link += "&list=" + url.QueryEscape(list)
+	}
+	return link
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// DefaultRemoteTimeout bounds every request to a remote server
+const DefaultRemoteTimeout = 5 * time.Second
+
+// errUnreachable wraps failures to reach the remote server, as opposed to
+// errors the server answered with
+var errUnreachable = errors.New("server unreachable")
+
+// remoteOptions configures the connection to a God Says server
+type remoteOptions struct {
+	URL string
+	// Token is sent as a bearer token, for servers behind authenticating proxies
+	Token string
+	// CAFile is a PEM bundle of additional certificate authorities
+	CAFile string
+	// CertFile and KeyFile are a client certificate for mutual TLS
+	CertFile string
+	KeyFile  string
+	Insecure bool
+	Timeout  time.Duration
+}
+
+// remoteClient fetches messages from a God Says server
+type remoteClient struct {
+	base   *url.URL
+	token  string
+	client *http.Client
+}
+
+// newRemoteClient creates a client for the server at opts.URL
+func newRemoteClient(opts remoteOptions) (*remoteClient, error) {
+	base, err := url.Parse(strings.TrimSuffix(opts.URL, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid remote URL %q: must be http(s)://host[:port]", opts.URL)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.Insecure}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+
+	return &remoteClient{
+		base:   base,
+		token:  opts.Token,
+		client: &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// speak fetches a message, seeded when seed is not nil
+func (c *remoteClient) speak(amount int, seed *int64, list string) (server.GodResponse, error) {
+	query := url.Values{}
+	query.Set("amount", strconv.Itoa(amount))
+	if seed != nil {
+		query.Set("seed", strconv.FormatInt(*seed, 10))
+	}
+	if list != "" {
+		query.Set("list", list)
+	}
+
+	var response server.GodResponse
+	err := c.get("/json", query, &response)
+	return response, err
+}
+
+// today fetches the server's message of the day, which follows the
+// server's timezone and namespace
+func (c *remoteClient) today(amount int, list string) (server.DayResponse, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("amount", strconv.Itoa(amount))
+	if list != "" {
+		query.Set("list", list)
+	}
+
+	var response server.DayResponse
+	err := c.get("/today", query, &response)
+	return response, err
+}
+
+// get requests path and decodes the JSON response into v. Transport errors
+// and gateway errors wrap errUnreachable.
+func (c *remoteClient) get(path string, query url.Values, v any) error {
+	endpoint := *c.base
+	endpoint.Path += path
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "godsays/"+internal.Version)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %s", errUnreachable, resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		var serverErr server.ErrorResponse
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(body, &serverErr) == nil && serverErr.Message != "" {
+			return fmt.Errorf("server returned %s: %s", resp.Status, serverErr.Message)
+		}
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding server response: %w", err)
+	}
+	return nil
+}
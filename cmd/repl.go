@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// replHelp describes the REPL commands
+const replHelp = `Press Enter to hear God speak, or type a question to ask the oracle.
+
+Commands:
+  :amount N        Words per message (%d - %d)
+  :seed N          Make the following messages reproducible, :seed off to stop
+  :list NAME       Switch wordlist: happy or NAME.txt in -data-dir
+  :format F        Output format: text or json
+  :history         Show the input history
+  :help            Show this help
+  :quit            Leave (or Ctrl-D)
+`
+
+// replSession is the state of an interactive session
+type replSession struct {
+	out     io.Writer
+	editor  *lineEditor
+	dataDir string
+	god     *internal.God
+	list    string
+	amount  int
+	format  string
+	// terminal wraps and colors text output
+	terminal internal.TerminalOptions
+	// rng draws message seeds after :seed, so sessions can be replayed
+	rng *rand.Rand
+	// messages records generated messages with -save
+	messages *internal.History
+}
+
+// replCommand starts an interactive session on the terminal
+var replCommand = &command{
+	name:    "repl",
+	summary: "Talk to God interactively. Type :help inside for commands.",
+	setup:   setupREPL,
+}
+
+// setupREPL defines the repl flags
+func setupREPL(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "amount", "data-dir", "no-wrap", "color", "save", "history-file")
+	defaultHistory, err := historyFile()
+	if err != nil {
+		defaultHistory = ""
+	}
+	history := fs.String("history", defaultHistory, "File the input history is kept in, empty to keep none")
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		if err := g.validateAmount(); err != nil {
+			return err
+		}
+		terminal, err := g.terminalOptions(os.Stdout)
+		if err != nil {
+			return err
+		}
+		messages, err := g.history()
+		if err != nil {
+			return err
+		}
+		return runREPL(g.amount, g.dataDir, *history, terminal, messages)
+	}
+}
+
+// runREPL talks to God until the input ends
+func runREPL(amount int, dataDir, historyPath string, terminal internal.TerminalOptions, messages *internal.History) error {
+	god, err := internal.NewGod(amount)
+	if err != nil {
+		return fmt.Errorf("failed to initialize God Says %w", err)
+	}
+
+	session := &replSession{
+		out:      os.Stdout,
+		editor:   newLineEditor(historyPath),
+		dataDir:  dataDir,
+		god:      god,
+		list:     server.DefaultList,
+		amount:   amount,
+		format:   "text",
+		terminal: terminal,
+		messages: messages,
+	}
+	fmt.Fprintln(session.out, "God says. Press Enter to listen, ask a question, or type :help.")
+	return session.run()
+}
+
+// run reads and executes lines until the input ends or :quit
+func (s *replSession) run() error {
+	for {
+		line, err := s.editor.readLine("god> ")
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if err := s.editor.addHistory(line); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save history: %v\n", err)
+		}
+
+		quit, err := s.execute(line)
+		if err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// execute runs a single line: a command, a question or an empty line
+func (s *replSession) execute(line string) (bool, error) {
+	if line == "" {
+		return false, s.speak()
+	}
+	if !strings.HasPrefix(line, ":") {
+		return false, s.ask(line)
+	}
+
+	command, arg, _ := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+	arg = strings.TrimSpace(arg)
+	switch command {
+	case "amount":
+		amount, err := strconv.Atoi(arg)
+		if err != nil {
+			return false, fmt.Errorf("amount must be a number")
+		}
+		if err := s.god.SetAmount(amount); err != nil {
+			return false, err
+		}
+		s.amount = amount
+	case "seed":
+		if arg == "off" || arg == "" {
+			s.rng = nil
+			return false, nil
+		}
+		seed, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("seed must be a 64-bit integer or off")
+		}
+		s.rng = rand.New(rand.NewSource(seed))
+	case "list":
+		return false, s.switchList(arg)
+	case "format":
+		if arg != "text" && arg != "json" {
+			return false, fmt.Errorf("format must be text or json")
+		}
+		s.format = arg
+	case "history":
+		for i, entry := range s.editor.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, entry)
+		}
+	case "help":
+		fmt.Fprintf(s.out, replHelp, internal.MinAmount, internal.MaxAmount)
+	case "quit", "exit", "q":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command :%s, try :help", command)
+	}
+	return false, nil
+}
+
+// speak prints a message, seeded when the session has a seed
+func (s *replSession) speak() error {
+	response := server.GodResponse{List: s.list, Amount: s.amount}
+	var err error
+	if s.rng != nil {
+		seed := s.rng.Int63()
+		response.Words, err = s.god.SpeakSeededWords(s.amount, seed)
+		response.Seed = &seed
+	} else {
+		response.Words, err = s.god.SpeakWords(s.amount)
+	}
+	if err != nil {
+		return err
+	}
+	response.GodSays = strings.Join(response.Words, " ")
+	record(s.messages, internal.HistoryEntry{Source: "repl", Kind: "speak", GodSays: response.GodSays, Words: response.Words, List: s.list, Amount: s.amount, Seed: response.Seed})
+
+	if s.format == "json" {
+		return s.printJSON(response)
+	}
+	fmt.Fprintln(s.out, internal.FormatTerminal(response.Words, s.terminal))
+	return nil
+}
+
+// ask prints the oracle's answer to question
+func (s *replSession) ask(question string) error {
+	seed, err := internal.QuestionSeed(question, "")
+	if err != nil {
+		return err
+	}
+	answer, err := s.god.SpeakSeeded(s.amount, seed)
+	if err != nil {
+		return err
+	}
+	record(s.messages, internal.HistoryEntry{Source: "repl", Kind: "ask", GodSays: answer, List: s.list, Amount: s.amount, Seed: &seed, Question: question})
+
+	if s.format == "json" {
+		return s.printJSON(server.AskResponse{Question: question, GodSays: answer, List: s.list, Amount: s.amount, Seed: seed})
+	}
+	fmt.Fprintln(s.out, internal.FormatTerminal(strings.Fields(answer), s.terminal))
+	return nil
+}
+
+// switchList loads the built-in wordlist or NAME.txt from the data directory
+func (s *replSession) switchList(name string) error {
+	if name == "" {
+		return fmt.Errorf("usage: :list NAME")
+	}
+	god, err := openList(s.dataDir, name, s.amount)
+	if err != nil {
+		return err
+	}
+
+	s.god, s.list = god, name
+	return nil
+}
+
+// openList loads the built-in wordlist or NAME.txt from dataDir
+func openList(dataDir, name string, amount int) (*internal.God, error) {
+	switch {
+	case name == server.DefaultList:
+		return internal.NewGod(amount)
+	case dataDir == "":
+		return nil, fmt.Errorf("%w: %q (set -data-dir to use saved wordlists)", server.ErrUnknownList, name)
+	case filepath.Base(name) != name:
+		return nil, server.ErrInvalidListName
+	default:
+		return loadWordlist(filepath.Join(dataDir, name+".txt"), amount)
+	}
+}
+
+// loadWordlist reads a wordlist file into a new God
+func loadWordlist(path string, amount int) (*internal.God, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	words, err := internal.ParseWords(f)
+	if err != nil {
+		return nil, err
+	}
+	return internal.NewGodWithWords(words, amount)
+}
+
+// printJSON prints v as a single line of JSON
+func (s *replSession) printJSON(v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, string(body))
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+)
+
+// serveCommand runs the HTTP server
+var serveCommand = &command{
+	name:    "serve",
+	summary: "Start the HTTP server",
+	setup:   setupServe,
+}
+
+// setupServe defines the serve flags
+func setupServe(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "tz", "namespace", "data-dir", "base-url", "save", "history-file", "favorites-file")
+	defaults := server.DefaultConfig()
+	var (
+		host       = fs.String("host", defaults.Host, "The HTTP server host")
+		port       = fs.Int("port", defaults.Port, "The listening port of HTTP server")
+		adminToken = fs.String("admin-token", os.Getenv("GODSAYS_ADMIN_TOKEN"), "Bearer token enabling the /admin routes (defaults to $GODSAYS_ADMIN_TOKEN)")
+
+		corsOrigins     = fs.String("cors-origins", "*", "Comma separated CORS origins, wildcards like https://*.example.com allowed")
+		corsMethods     = fs.String("cors-methods", "GET,POST,OPTIONS", "Comma separated CORS methods")
+		corsHeaders     = fs.String("cors-headers", "Content-Type", "Comma separated CORS request headers")
+		corsCredentials = fs.Bool("cors-credentials", false, "Allow credentialed CORS requests from the listed -cors-origins, which must not include *")
+		corsMaxAge      = fs.Duration("cors-max-age", 24*time.Hour, "How long browsers may cache CORS preflight results")
+		csp             = fs.String("csp", defaults.SecurityHeaders.ContentSecurityPolicy, "Content-Security-Policy header, empty to omit")
+		hsts            = fs.String("hsts", "", "Strict-Transport-Security header, empty to omit")
+		adminAddr       = fs.String("admin-addr", "", "Address serving pprof, expvar and build info, e.g. 127.0.0.1:6060")
+		drainDelay      = fs.Duration("drain-delay", defaults.DrainDelay, "How long /readyz fails before shutting down on SIGTERM")
+		historyMax      = fs.Int("history-max", defaults.HistoryMaxEntries, "Number of recorded messages kept with -save, oldest dropped first; 0 keeps all")
+		favorites       = fs.Bool("favorites", false, "Let clients bless messages with POST /favorites, stored in -favorites-file and shared at /m/{id}; open to anyone without -admin-token")
+	)
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		loc, err := g.location()
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Starting God Says HTTP server host: %s port: %d", *host, *port)
+		cfg := server.DefaultConfig()
+		cfg.Host = *host
+		cfg.Port = *port
+		cfg.DataDir = g.dataDir
+		cfg.AdminToken = *adminToken
+		cfg.CORS = server.CORSConfig{
+			AllowedOrigins:   splitList(*corsOrigins),
+			AllowedMethods:   splitList(*corsMethods),
+			AllowedHeaders:   splitList(*corsHeaders),
+			AllowCredentials: *corsCredentials,
+			MaxAge:           *corsMaxAge,
+		}
+		cfg.SecurityHeaders.ContentSecurityPolicy = *csp
+		cfg.SecurityHeaders.StrictTransportSecurity = *hsts
+		cfg.DrainDelay = *drainDelay
+		cfg.AdminAddr = *adminAddr
+		cfg.Location = loc
+		cfg.Namespace = g.namespace
+		cfg.BaseURL = g.baseURL
+		if g.save {
+			// served messages are recorded and browsable at /history
+			cfg.HistoryFile = g.historyFile
+			cfg.HistoryMaxEntries = *historyMax
+		}
+		if *favorites {
+			cfg.FavoritesFile = g.favoritesFile
+		}
+		if err := server.RunServer(cfg); err != nil {
+			return fmt.Errorf("running God Says HTTP server: %w", err)
+		}
+		return nil
+	}
+}
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
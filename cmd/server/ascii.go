@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// renderASCII renders message as ASCII art configured by the request. On
+// failure it writes an error response and returns false.
+func (s *Server) renderASCII(w http.ResponseWriter, r *http.Request, message string) ([]byte, bool) {
+	opts, err := parseASCIIOptions(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return nil, false
+	}
+
+	art, err := internal.RenderASCII(message, opts)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return nil, false
+	}
+	return []byte(art), true
+}
+
+// parseASCIIOptions parses the width, character, border and banner parameters
+func parseASCIIOptions(r *http.Request) (internal.ASCIIOptions, error) {
+	opts := internal.DefaultASCIIOptions()
+	query := r.URL.Query()
+
+	if value := query.Get("width"); value != "" {
+		width, err := strconv.Atoi(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid width parameter: must be a number")
+		}
+		opts.Width = width
+	}
+	if character := query.Get("character"); character != "" {
+		opts.Character = character
+	}
+	if border := query.Get("border"); border != "" {
+		opts.Border = border
+	}
+	if value := query.Get("banner"); value != "" {
+		banner, err := strconv.ParseBool(value)
+		if err != nil {
+			return opts, fmt.Errorf("invalid banner parameter: must be a boolean")
+		}
+		opts.Banner = banner
+	}
+	return opts, nil
+}
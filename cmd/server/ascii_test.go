@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerASCII(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/?format=ascii&amount=3&seed=9&character=cow&border=plain&width=20", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.HasPrefix(body, " _") || !strings.Contains(body, "(oo)") {
+		t.Errorf("Expected a cow with a plain bubble, got:\n%s", body)
+	}
+	if rr.Header().Get("ETag") == "" {
+		t.Error("Expected seeded ASCII art to be cacheable")
+	}
+}
+
+func TestServerASCIIBanner(t *testing.T) {
+	server := newDailyTestServer(t, time.UTC, time.Now())
+
+	req, _ := http.NewRequest("GET", "/day/2024-01-01?format=ascii&amount=1&banner=true&width=80", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "█") {
+		t.Errorf("Expected a banner, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestServerASCIIInvalid(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, query := range []string{"format=html", "format=ascii&width=x", "format=ascii&width=1", "format=ascii&character=dragon", "format=ascii&border=double", "format=ascii&banner=maybe", "format=ascii&banner=1&amount=100"} {
+		req, _ := http.NewRequest("GET", "/?"+query, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DeterministicMaxAge is how long clients may cache deterministic responses.
+// Wordlists can be replaced at runtime, so caches revalidate with the ETag
+// afterwards rather than treating the response as immutable.
+const DeterministicMaxAge = 24 * time.Hour
+
+// writeCacheable writes body with caching headers. Deterministic responses get
+// a strong ETag and a long-lived Cache-Control and honor If-None-Match; random
+// ones are never stored.
+func (s *Server) writeCacheable(w http.ResponseWriter, r *http.Request, contentType string, body []byte, deterministic bool) {
+	var maxAge time.Duration
+	if deterministic {
+		maxAge = DeterministicMaxAge
+	}
+	s.writeCacheableFor(w, r, contentType, body, maxAge)
+}
+
+// writeCacheableFor writes body allowing caches to keep it for maxAge. A zero
+// maxAge marks the response as random and never stored.
+func (s *Server) writeCacheableFor(w http.ResponseWriter, r *http.Request, contentType string, body []byte, maxAge time.Duration) {
+	if maxAge <= 0 {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+		return
+	}
+
+	// links built from the Host header must not be shared between clients
+	visibility := "public"
+	if slices.Contains(w.Header().Values("Vary"), "Host") {
+		visibility = "private"
+	}
+	etag := strongETag(body)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// strongETag derives a strong entity tag from the response body
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using the
+// weak comparison required by RFC 9110
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSeededResponsesAreCacheable(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	r := server.routes()
+
+	for _, endpoint := range []string{"/?seed=42&amount=5", "/json?seed=42&amount=5"} {
+		req, _ := http.NewRequest("GET", endpoint, nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		etag := rr.Header().Get("ETag")
+		if etag == "" || etag[0] != '"' {
+			t.Fatalf("Expected strong ETag for %s, got '%s'", endpoint, etag)
+		}
+		if cc := rr.Header().Get("Cache-Control"); cc != "public, max-age=86400" {
+			t.Errorf("Expected long-lived Cache-Control for %s, got '%s'", endpoint, cc)
+		}
+
+		// The same seed produces the same body and ETag
+		again := httptest.NewRecorder()
+		r.ServeHTTP(again, req)
+		if again.Body.String() != rr.Body.String() || again.Header().Get("ETag") != etag {
+			t.Errorf("Expected identical response for %s", endpoint)
+		}
+
+		for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+			conditional, _ := http.NewRequest("GET", endpoint, nil)
+			conditional.Header.Set("If-None-Match", header)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, conditional)
+			if rr.Code != http.StatusNotModified {
+				t.Errorf("Expected status %d for If-None-Match %s, got %d", http.StatusNotModified, header, rr.Code)
+			}
+			if rr.Body.Len() != 0 {
+				t.Errorf("Expected empty body on 304, got %q", rr.Body.String())
+			}
+		}
+
+		conditional, _ := http.NewRequest("GET", endpoint, nil)
+		conditional.Header.Set("If-None-Match", `"stale"`)
+		rr = httptest.NewRecorder()
+		r.ServeHTTP(rr, conditional)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status %d for stale ETag, got %d", http.StatusOK, rr.Code)
+		}
+	}
+}
+
+func TestRandomResponsesAreNotCached(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/?amount=5", nil)
+	req.Header.Set("If-None-Match", "*")
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("ETag") != "" {
+		t.Errorf("Expected no ETag for random output, got '%s'", rr.Header().Get("ETag"))
+	}
+	if cc := rr.Header().Get("Cache-Control"); cc != "no-cache, no-store, must-revalidate" {
+		t.Errorf("Expected no-store Cache-Control, got '%s'", cc)
+	}
+}
+
+func TestSeedParameter(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/json?seed=-7&amount=3", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	var response GodResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if response.Seed == nil || *response.Seed != -7 {
+		t.Errorf("Expected seed -7 in response, got %v", response.Seed)
+	}
+	if response.Amount != 3 {
+		t.Errorf("Expected amount 3, got %d", response.Amount)
+	}
+
+	for _, seed := range []string{"abc", "1.5", "99999999999999999999"} {
+		req, _ := http.NewRequest("GET", "/?seed="+seed, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for seed %s, got %d", http.StatusBadRequest, seed, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/omid3699/god_says/internal"
+)
+
+// handleToday returns the message of the day in the configured timezone. It
+// may be cached until the day ends.
+func (s *Server) handleToday(w http.ResponseWriter, r *http.Request) {
+	now := s.now().In(s.config.Location)
+	s.writeDay(w, r, now, internal.NextDay(now).Sub(now))
+}
+
+// handleDay returns the message of a given YYYY-MM-DD date
+func (s *Server) handleDay(w http.ResponseWriter, r *http.Request) {
+	date, err := internal.ParseDay(mux.Vars(r)["date"], s.config.Location)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", "date must be a valid YYYY-MM-DD date")
+		return
+	}
+	s.writeDay(w, r, date, DeterministicMaxAge)
+}
+
+// dayMessage generates the message of the day for date
+func (s *Server) dayMessage(list *wordlist, date time.Time, amount int) (DayResponse, error) {
+	seed := internal.DaySeed(date, s.config.Namespace)
+	words, err := list.god.SpeakSeededWords(amount, seed)
+	if err != nil {
+		return DayResponse{}, err
+	}
+
+	return DayResponse{
+		Date:      date.Format(internal.DateSaltLayout),
+		Namespace: s.config.Namespace,
+		Timezone:  s.config.Location.String(),
+		GodSays:   strings.Join(words, " "),
+		Words:     words,
+		List:      list.name,
+		Amount:    amount,
+		Seed:      seed,
+	}, nil
+}
+
+// writeDay writes the message of the day for date as text or, with
+// ?format=json or ?format=ascii, as JSON or ASCII art
+func (s *Server) writeDay(w http.ResponseWriter, r *http.Request, date time.Time, maxAge time.Duration) {
+	amount, err := s.parseAmount(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	list, err := s.parseList(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", err.Error())
+		return
+	}
+
+	day, err := s.dayMessage(list, date, amount)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+		s.writeCacheableFor(w, r, "text/plain; charset=utf-8", []byte(day.GodSays), maxAge)
+	case "ascii":
+		body, ok := s.renderASCII(w, r, day.GodSays)
+		if !ok {
+			return
+		}
+		s.writeCacheableFor(w, r, "text/plain; charset=utf-8", body, maxAge)
+	case "json":
+		body, err := json.Marshal(day)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusInternalServerError, "encoding_error", "Failed to encode response")
+			return
+		}
+		s.writeCacheableFor(w, r, "application/json", append(body, '\n'), maxAge)
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("unsupported format %q", format))
+	}
+}
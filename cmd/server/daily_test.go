@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newDailyTestServer(t *testing.T, loc *time.Location, now time.Time) *Server {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.Location = loc
+	cfg.Namespace = "team"
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server.now = func() time.Time { return now }
+	return server
+}
+
+func getDay(t *testing.T, server *Server, target string) (*httptest.ResponseRecorder, DayResponse) {
+	t.Helper()
+
+	req, _ := http.NewRequest("GET", target, nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	var day DayResponse
+	if rr.Code == http.StatusOK {
+		if err := json.Unmarshal(rr.Body.Bytes(), &day); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+	}
+	return rr, day
+}
+
+func TestServerToday(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	// 23:30 UTC on March 9th is already March 10th in Berlin
+	now := time.Date(2024, 3, 9, 23, 30, 0, 0, time.UTC)
+	server := newDailyTestServer(t, berlin, now)
+
+	rr, today := getDay(t, server, "/today?format=json")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if today.Date != "2024-03-10" || today.Namespace != "team" || today.GodSays == "" {
+		t.Errorf("Unexpected message of the day: %+v", today)
+	}
+
+	// Cacheable until midnight in Berlin, 30 minutes away
+	if cc := rr.Header().Get("Cache-Control"); cc != "public, max-age=84600" {
+		t.Errorf("Expected cache until the end of the day, got '%s'", cc)
+	}
+
+	_, day := getDay(t, server, "/day/2024-03-10?format=json")
+	if day.GodSays != today.GodSays {
+		t.Errorf("Expected /today to match /day for the same date, got %q and %q", today.GodSays, day.GodSays)
+	}
+
+	_, yesterday := getDay(t, server, "/day/2024-03-09?format=json")
+	if yesterday.GodSays == today.GodSays {
+		t.Errorf("Expected a different message for another day")
+	}
+}
+
+func TestServerDay(t *testing.T) {
+	server := newDailyTestServer(t, time.UTC, time.Now())
+
+	req, _ := http.NewRequest("GET", "/day/2024-01-01?amount=4", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("Expected plain text by default, got '%s'", rr.Header().Get("Content-Type"))
+	}
+	if rr.Header().Get("ETag") == "" {
+		t.Error("Expected an ETag for a fixed date")
+	}
+
+	again := newDailyTestServer(t, time.UTC, time.Now())
+	rr2 := httptest.NewRecorder()
+	again.routes().ServeHTTP(rr2, req)
+	if rr.Body.String() != rr2.Body.String() {
+		t.Errorf("Expected a stable message across servers, got %q and %q", rr.Body.String(), rr2.Body.String())
+	}
+
+	for _, target := range []string{"/day/2023-02-30", "/day/2024-01-01?format=xml", "/day/2024-01-01?amount=0"} {
+		rr, _ := getDay(t, server, target)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, target, rr.Code)
+		}
+	}
+
+	if rr, _ := getDay(t, server, "/day/yesterday"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a malformed date, got %d", http.StatusNotFound, rr.Code)
+	}
+}
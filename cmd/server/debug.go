@@ -0,0 +1,32 @@
+package server
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+func init() {
+	expvar.Publish("build", expvar.Func(func() any { return internal.GetBuildInfo() }))
+}
+
+// debugRoutes builds the handler served on the admin listener. It is kept
+// separate from routes so profiling data is never exposed on the public port.
+func (s *Server) debugRoutes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/buildinfo", s.handleBuildInfo)
+	return loggingMiddleware(mux)
+}
+
+// handleBuildInfo returns version information about the running binary
+func (s *Server) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, internal.GetBuildInfo())
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+func TestDebugRoutes(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	handler := server.debugRoutes()
+
+	for _, endpoint := range []string{"/debug/pprof/", "/debug/vars", "/debug/buildinfo"} {
+		req, _ := http.NewRequest("GET", endpoint, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusOK, endpoint, rr.Code)
+		}
+		if endpoint == "/debug/vars" && !strings.Contains(rr.Body.String(), `"build"`) {
+			t.Errorf("Expected expvar output to contain build info, got %s", rr.Body.String())
+		}
+	}
+}
+
+func TestDebugBuildInfo(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/debug/buildinfo", nil)
+	rr := httptest.NewRecorder()
+	server.debugRoutes().ServeHTTP(rr, req)
+
+	var info internal.BuildInfo
+	if err := json.Unmarshal(rr.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("Expected Go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+	if info.Version == "" {
+		t.Error("Expected non-empty version")
+	}
+}
+
+func TestDebugRoutesNotPublic(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, endpoint := range []string{"/debug/pprof/", "/debug/vars", "/debug/buildinfo"} {
+		req, _ := http.NewRequest("GET", endpoint, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected %s to be absent from the public router, got %d", endpoint, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/omid3699/god_says/internal"
+)
+
+// handleAddFavorite blesses a message posted as JSON or form data and
+// returns its permalink
+func (s *Server) handleAddFavorite(w http.ResponseWriter, r *http.Request) {
+	var req FavoriteRequest
+	body := http.MaxBytesReader(w, r.Body, MaxQuestionSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_body", "Failed to decode JSON body")
+			return
+		}
+	} else {
+		r.Body = body
+		if err := r.ParseForm(); err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_body", "Failed to parse form body")
+			return
+		}
+		req.HistoryID = r.PostForm.Get("history_id")
+		req.List = r.PostForm.Get("list")
+		if value := r.PostForm.Get("seed"); value != "" {
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", "invalid seed parameter: must be a 64-bit integer")
+				return
+			}
+			req.Seed = &seed
+		}
+		if value := r.PostForm.Get("amount"); value != "" {
+			amount, err := strconv.Atoi(value)
+			if err != nil {
+				s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", "invalid amount parameter: must be a number")
+				return
+			}
+			req.Amount = amount
+		}
+	}
+
+	var entry internal.HistoryEntry
+	switch {
+	case req.HistoryID != "":
+		if s.history == nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", "history_id requires the server to record its history")
+			return
+		}
+		recorded, ok, err := s.history.Get(req.HistoryID)
+		if err != nil {
+			log.Printf("Failed to read history: %v", err)
+			s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to read history")
+			return
+		}
+		if !ok {
+			s.writeErrorResponse(w, http.StatusNotFound, "not_found", fmt.Sprintf("no message %q in the history", req.HistoryID))
+			return
+		}
+		// the permalink keeps the ID the message was recorded with
+		if favorite, ok, err := s.favorites.Get(recorded.ID); err == nil && ok {
+			s.writeFavorite(w, r, http.StatusOK, favorite)
+			return
+		}
+		entry = recorded
+		entry.Time = s.now()
+		entry.Question = ""
+	case req.Seed != nil:
+		amount := req.Amount
+		if amount == 0 {
+			amount = internal.DefaultAmount
+		}
+		if amount < internal.MinAmount || amount > internal.MaxAmount {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", internal.ErrInvalidAmount.Error())
+			return
+		}
+		name := req.List
+		if name == "" {
+			name = DefaultList
+		}
+		list, err := s.lists.get(name)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", fmt.Sprintf("%v: %q", err, name))
+			return
+		}
+		// blessing the same message again returns its permalink
+		id := internal.SeedHistoryID(list.name, amount, *req.Seed)
+		if favorite, ok, err := s.favorites.Get(id); err == nil && ok {
+			s.writeFavorite(w, r, http.StatusOK, favorite)
+			return
+		}
+		words, err := list.god.SpeakSeededWords(amount, *req.Seed)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+			return
+		}
+		entry = internal.HistoryEntry{ID: id, Time: s.now(), Kind: "speak", GodSays: strings.Join(words, " "), Words: words, List: list.name, Amount: amount, Seed: req.Seed}
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", "history_id or seed is required")
+		return
+	}
+
+	entry.Source = "server"
+	entry, err := s.favorites.Add(entry)
+	if err != nil {
+		log.Printf("Failed to store favorite: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to store favorite")
+		return
+	}
+	w.Header().Set("Location", "/m/"+entry.ID)
+	s.writeFavorite(w, r, http.StatusCreated, entry)
+}
+
+// writeFavorite writes a favorite with its permalink as JSON
+func (s *Server) writeFavorite(w http.ResponseWriter, r *http.Request, status int, entry internal.HistoryEntry) {
+	s.writeJSON(w, status, s.favoriteResponse(w, r, entry))
+}
+
+// favoriteResponse pairs a favorite with its permalink. Questions stay out,
+// as favorites blessed by the CLI may keep them.
+func (s *Server) favoriteResponse(w http.ResponseWriter, r *http.Request, entry internal.HistoryEntry) FavoriteResponse {
+	entry.Question = ""
+	return FavoriteResponse{HistoryEntry: entry, URL: s.baseURL(w, r) + "/m/" + entry.ID}
+}
+
+// handleFavorite re-renders a blessed message as text or, with ?format=, as
+// ascii, json, png, svg, wav, qr.png, qr.svg or qr.txt. Favorites never
+// change, so every format may be cached.
+func (s *Server) handleFavorite(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	entry, ok, err := s.favorites.Get(id)
+	if err != nil {
+		log.Printf("Failed to read favorites: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to read favorites")
+		return
+	}
+	if !ok {
+		s.writeErrorResponse(w, http.StatusNotFound, "not_found", fmt.Sprintf("no favorite %q", id))
+		return
+	}
+
+	var (
+		contentType string
+		body        []byte
+	)
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "text":
+		contentType, body = "text/plain; charset=utf-8", []byte(entry.GodSays)
+	case "ascii":
+		contentType = "text/plain; charset=utf-8"
+		body, ok = s.renderASCII(w, r, entry.GodSays)
+	case "json":
+		if body, err = json.Marshal(s.favoriteResponse(w, r, entry)); err != nil {
+			s.writeErrorResponse(w, http.StatusInternalServerError, "encoding_error", "Failed to encode response")
+			return
+		}
+		contentType, body = "application/json", append(body, '\n')
+	case "png":
+		contentType = "image/png"
+		body, ok = s.renderImage(w, r, entry.GodSays, internal.RenderPNG)
+	case "svg":
+		contentType = "image/svg+xml"
+		body, ok = s.renderImage(w, r, entry.GodSays, internal.RenderSVG)
+	case "wav":
+		opts, _, err := parseSongOptions(r)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		contentType = "audio/wav"
+		body, ok = s.renderSong(w, internal.SongFromMessage(entry.GodSays), opts)
+	case "qr.png", "qr.svg", "qr.txt":
+		body, contentType, ok = s.renderFavoriteQR(w, r, entry, format)
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("unsupported format %q", format))
+		return
+	}
+	if !ok {
+		return
+	}
+	s.writeCacheable(w, r, contentType, body, true)
+}
+
+// renderFavoriteQR encodes a favorite, or with ?content=link its permalink,
+// as a QR code in format
+func (s *Server) renderFavoriteQR(w http.ResponseWriter, r *http.Request, entry internal.HistoryEntry, format string) ([]byte, string, bool) {
+	var data string
+	switch content := r.URL.Query().Get("content"); content {
+	case "", "message":
+		data = entry.GodSays
+	case "link":
+		data = s.baseURL(w, r) + "/m/" + entry.ID
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("content must be message or link, got %q", content))
+		return nil, "", false
+	}
+
+	var (
+		contentType string
+		render      qrRenderer
+	)
+	switch format {
+	case "qr.png":
+		contentType = "image/png"
+		render = func(q *internal.QRCode, w io.Writer, scale int) error {
+			return q.WritePNG(w, scale)
+		}
+	case "qr.svg":
+		contentType = "image/svg+xml"
+		render = func(q *internal.QRCode, w io.Writer, scale int) error {
+			return q.WriteSVG(w, scale)
+		}
+	default:
+		invert, err := parseInvert(r)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return nil, "", false
+		}
+		contentType = "text/plain; charset=utf-8"
+		render = func(q *internal.QRCode, w io.Writer, _ int) error {
+			return q.WriteTerminal(w, invert)
+		}
+	}
+
+	body, ok := s.renderQR(w, r, data, render)
+	return body, contentType, ok
+}
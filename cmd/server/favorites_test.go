@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+func TestFavorites(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FavoritesFile = filepath.Join(t.TempDir(), "favorites.jsonl")
+	cfg.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	cfg.BaseURL = "https://god.example.com"
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/json?amount=5&seed=42", nil))
+	var spoken GodResponse
+	json.Unmarshal(rr.Body.Bytes(), &spoken)
+
+	// bless the message a seed reproduces
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/favorites", strings.NewReader(`{"seed": 42, "amount": 5}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	var favorite FavoriteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &favorite); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if favorite.GodSays != spoken.GodSays || len(favorite.ID) != 8 {
+		t.Errorf("Expected the seeded message with a short ID, got %+v", favorite)
+	}
+	if favorite.URL != "https://god.example.com/m/"+favorite.ID || rr.Header().Get("Location") != "/m/"+favorite.ID {
+		t.Errorf("Expected a permalink, got %q and Location %q", favorite.URL, rr.Header().Get("Location"))
+	}
+
+	// blessing the same message again returns the same favorite
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/favorites", strings.NewReader(`{"seed": 42, "amount": 5, "list": "happy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rr, req)
+	var again FavoriteResponse
+	json.Unmarshal(rr.Body.Bytes(), &again)
+	if rr.Code != http.StatusOK || again.ID != favorite.ID {
+		t.Errorf("Expected status %d and ID %q, got %d and %q", http.StatusOK, favorite.ID, rr.Code, again.ID)
+	}
+	if page, _ := server.favorites.Query(internal.HistoryQuery{}); page.Total != 1 {
+		t.Errorf("Expected 1 stored favorite, got %d", page.Total)
+	}
+
+	formats := map[string]string{
+		"":       "text/plain; charset=utf-8",
+		"ascii":  "text/plain; charset=utf-8",
+		"json":   "application/json",
+		"png":    "image/png",
+		"svg":    "image/svg+xml",
+		"wav":    "audio/wav",
+		"qr.png": "image/png",
+		"qr.svg": "image/svg+xml",
+		"qr.txt": "text/plain; charset=utf-8",
+	}
+	for format, contentType := range formats {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/m/"+favorite.ID+"?format="+format, nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status %d for format %q, got %d", http.StatusOK, format, rr.Code)
+			continue
+		}
+		if got := rr.Header().Get("Content-Type"); got != contentType {
+			t.Errorf("Expected Content-Type %q for format %q, got %q", contentType, format, got)
+		}
+		if !strings.Contains(rr.Header().Get("Cache-Control"), "public") {
+			t.Errorf("Expected format %q to be cacheable", format)
+		}
+	}
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/m/"+favorite.ID, nil))
+	if rr.Body.String() != spoken.GodSays {
+		t.Errorf("Expected %q, got %q", spoken.GodSays, rr.Body.String())
+	}
+
+	// bless a recorded message as form data, keeping its ID
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/history?limit=1", nil))
+	var page struct {
+		Entries []FavoriteResponse `json:"entries"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &page)
+	historyID := page.Entries[0].ID
+	for _, status := range []int{http.StatusCreated, http.StatusOK} {
+		rr = httptest.NewRecorder()
+		req = httptest.NewRequest("POST", "/favorites", strings.NewReader(url.Values{"history_id": {historyID}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(rr, req)
+		if rr.Code != status {
+			t.Fatalf("Expected status %d, got %d: %s", status, rr.Code, rr.Body.String())
+		}
+		json.Unmarshal(rr.Body.Bytes(), &favorite)
+		if favorite.ID != historyID {
+			t.Errorf("Expected the history ID %q, got %q", historyID, favorite.ID)
+		}
+	}
+
+	testCases := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"POST", "/favorites", `{}`, http.StatusBadRequest},
+		{"POST", "/favorites", `{"seed": 1, "amount": 100000}`, http.StatusBadRequest},
+		{"POST", "/favorites", `{"seed": 1, "list": "missing"}`, http.StatusNotFound},
+		{"POST", "/favorites", `{"history_id": "missing"}`, http.StatusNotFound},
+		{"GET", "/m/missing", "", http.StatusNotFound},
+		{"GET", "/m/" + favorite.ID + "?format=gif", "", http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		rr = httptest.NewRecorder()
+		req = httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rr, req)
+		if rr.Code != tc.status {
+			t.Errorf("Expected status %d for %s %s %s, got %d", tc.status, tc.method, tc.target, tc.body, rr.Code)
+		}
+	}
+}
+
+func TestFavoritesAdminToken(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.FavoritesFile = filepath.Join(t.TempDir(), "favorites.jsonl")
+	cfg.AdminToken = "s3cret"
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	for token, status := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "s3cret": http.StatusCreated} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/favorites", strings.NewReader(`{"seed": 7}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		router.ServeHTTP(rr, req)
+		if rr.Code != status {
+			t.Errorf("Expected status %d with token %q, got %d", status, token, rr.Code)
+		}
+	}
+}
+
+func TestFavoritesDisabled(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	for _, req := range []*http.Request{httptest.NewRequest("POST", "/favorites", nil), httptest.NewRequest("GET", "/m/abcdefgh", nil)} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d for %s %s without a favorites file, got %d", http.StatusNotFound, req.Method, req.URL.Path, rr.Code)
+		}
+	}
+}
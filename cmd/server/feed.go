@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+const (
+	// DefaultFeedDays is the number of days listed in feeds by default
+	DefaultFeedDays = 7
+	// DefaultCalendarDays is the number of upcoming days in calendars by default
+	DefaultCalendarDays = 30
+	// MaxFeedDays is the maximum number of days a feed or calendar may list
+	MaxFeedDays = 366
+)
+
+// handleRSS serves the daily messages as an RSS 2.0 feed
+func (s *Server) handleRSS(w http.ResponseWriter, r *http.Request) {
+	s.writeFeed(w, r, "application/rss+xml; charset=utf-8", DefaultFeedDays, internal.NewDailyFeed, internal.Feed.RSS)
+}
+
+// handleAtom serves the daily messages as an Atom 1.0 feed
+func (s *Server) handleAtom(w http.ResponseWriter, r *http.Request) {
+	s.writeFeed(w, r, "application/atom+xml; charset=utf-8", DefaultFeedDays, internal.NewDailyFeed, internal.Feed.Atom)
+}
+
+// handleCalendar serves the upcoming daily messages as an iCalendar file
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Disposition", `attachment; filename="godsays.ics"`)
+	s.writeFeed(w, r, "text/calendar; charset=utf-8", DefaultCalendarDays, internal.NewDailyCalendar,
+		func(f internal.Feed) ([]byte, error) { return f.ICalendar(), nil })
+}
+
+// feedBuilder builds a feed of daily messages from a wordlist
+type feedBuilder func(*internal.God, internal.FeedOptions) (internal.Feed, error)
+
+// writeFeed builds a feed of daily messages for the request and renders it
+func (s *Server) writeFeed(w http.ResponseWriter, r *http.Request, contentType string, defaultDays int, build feedBuilder, render func(internal.Feed) ([]byte, error)) {
+	amount, err := s.parseAmount(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	days, err := parseDays(r, defaultDays)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	list, err := s.parseList(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", err.Error())
+		return
+	}
+
+	feed, err := build(list.god, internal.FeedOptions{
+		Title:     fmt.Sprintf("God Says (%s)", s.config.Namespace),
+		BaseURL:   s.baseURL(w, r),
+		Query:     messageQuery(r),
+		Days:      days,
+		Amount:    amount,
+		Namespace: s.config.Namespace,
+		Location:  s.config.Location,
+		Now:       s.now,
+	})
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+		return
+	}
+
+	body, err := render(feed)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, "encoding_error", "Failed to encode feed")
+		return
+	}
+
+	now := s.now().In(s.config.Location)
+	s.writeCacheableFor(w, r, contentType, body, internal.NextDay(now).Sub(now))
+}
+
+// parseDays parses and validates the days parameter from request
+func parseDays(r *http.Request, fallback int) (int, error) {
+	daysStr := r.URL.Query().Get("days")
+	if daysStr == "" {
+		return fallback, nil
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid days parameter: must be a number")
+	}
+	if days < 1 || days > MaxFeedDays {
+		return 0, fmt.Errorf("days must be between 1 and %d", MaxFeedDays)
+	}
+	return days, nil
+}
+
+// messageQuery returns the request parameters that change a daily message,
+// so links reproduce the same message
+func messageQuery(r *http.Request) url.Values {
+	query := url.Values{}
+	for _, key := range []string{"list", "amount"} {
+		if value := r.URL.Query().Get(key); value != "" {
+			query.Set(key, value)
+		}
+	}
+	return query
+}
+
+// baseURL returns the public URL of the server, preferring the configured one.
+// Without one it trusts the Host header, so the response varies by Host and
+// writeCacheable keeps it out of shared caches.
+func (s *Server) baseURL(w http.ResponseWriter, r *http.Request) string {
+	if s.config.BaseURL != "" {
+		return strings.TrimSuffix(s.config.BaseURL, "/")
+	}
+	w.Header().Add("Vary", "Host")
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerFeeds(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	server := newDailyTestServer(t, time.UTC, now)
+	server.config.BaseURL = "https://godsays.example.com/"
+
+	testCases := map[string]string{
+		"/feed.rss?days=3&list=happy":  "application/rss+xml; charset=utf-8",
+		"/feed.atom?days=3&list=happy": "application/atom+xml; charset=utf-8",
+	}
+	for target, contentType := range testCases {
+		req, _ := http.NewRequest("GET", target, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, target, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != contentType {
+			t.Errorf("Expected content type %s for %s, got %s", contentType, target, ct)
+		}
+		if cc := rr.Header().Get("Cache-Control"); cc != "public, max-age=43200" {
+			t.Errorf("Expected feed to be cacheable until midnight, got '%s'", cc)
+		}
+
+		body := rr.Body.String()
+		for _, date := range []string{"2024-05-10", "2024-05-09", "2024-05-08"} {
+			if !strings.Contains(body, "https://godsays.example.com/day/"+date+"?list=happy") {
+				t.Errorf("Expected %s to link to %s, got %s", target, date, body)
+			}
+		}
+		if strings.Contains(body, "2024-05-07") {
+			t.Errorf("Expected only 3 days in %s", target)
+		}
+	}
+}
+
+func TestServerFeedDerivedBaseURL(t *testing.T) {
+	server := newDailyTestServer(t, time.UTC, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+
+	req, _ := http.NewRequest("GET", "http://localhost:3333/feed.rss?days=1", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if !strings.Contains(rr.Body.String(), "<link>http://localhost:3333/day/2024-05-10</link>") {
+		t.Errorf("Expected links derived from the request host, got %s", rr.Body.String())
+	}
+	// shared caches must not serve links to another Host
+	if cc := rr.Header().Get("Cache-Control"); !strings.HasPrefix(cc, "private,") {
+		t.Errorf("Expected a private Cache-Control, got '%s'", cc)
+	}
+	if !slices.Contains(rr.Header().Values("Vary"), "Host") {
+		t.Errorf("Expected Vary: Host, got %v", rr.Header().Values("Vary"))
+	}
+}
+
+func TestServerFeedInvalidDays(t *testing.T) {
+	server := newDailyTestServer(t, time.UTC, time.Now())
+
+	for _, days := range []string{"0", "-3", "367", "week"} {
+		req, _ := http.NewRequest("GET", "/feed.atom?days="+days, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for days %s, got %d", http.StatusBadRequest, days, rr.Code)
+		}
+	}
+}
+
+func TestServerCalendar(t *testing.T) {
+	server := newDailyTestServer(t, time.UTC, time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC))
+
+	get := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/calendar.ics?days=30", nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Errorf("Expected calendar content type, got %s", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "godsays.ics") {
+		t.Errorf("Expected attachment disposition, got %s", cd)
+	}
+
+	body := rr.Body.String()
+	if count := strings.Count(body, "BEGIN:VEVENT"); count != 30 {
+		t.Errorf("Expected 30 events, got %d", count)
+	}
+	if !strings.Contains(body, "DTSTART;VALUE=DATE:20240510") || !strings.Contains(body, "DTSTART;VALUE=DATE:20240608") {
+		t.Error("Expected events from today through 29 days ahead")
+	}
+
+	if again := get(); again.Body.String() != body || again.Header().Get("ETag") != rr.Header().Get("ETag") {
+		t.Error("Expected a stable calendar across requests")
+	}
+}
@@ -2,78 +2,189 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/omid3699/god_says/internal"
 )
 
-// handleRoot handles the root endpoint returning plain text
+// speech is a generated message together with the options that produced it
+type speech struct {
+	message string
+	words   []string
+	list    *wordlist
+	amount  int
+	seed    int64
+	seeded  bool
+}
+
+// handleRoot handles the root endpoint returning plain text or, with
+// ?format=ascii, the message in a speech bubble
 func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
-	amount, err := s.parseAmount(r)
-	if err != nil {
-		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "text" && format != "ascii" {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("unsupported format %q", format))
 		return
 	}
 
-	var message string
-	if amount == internal.DefaultAmount {
-		message = s.god.Speak()
-	} else {
-		message, err = s.god.SpeakWithAmount(amount)
-		if err != nil {
-			s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+	sp, ok := s.speak(w, r)
+	if !ok {
+		return
+	}
+
+	body := []byte(sp.message)
+	if format == "ascii" {
+		if body, ok = s.renderASCII(w, r, sp.message); !ok {
 			return
 		}
 	}
-	if message == "" {
-		s.writeErrorResponse(w, http.StatusInternalServerError, "empty_message", "Failed to generate message")
+	s.writeCacheable(w, r, "text/plain; charset=utf-8", body, sp.seeded)
+}
+
+// handleJSON handles the JSON endpoint
+func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+	sp, ok := s.speak(w, r)
+	if !ok {
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, message)
+	response := GodResponse{GodSays: sp.message, Words: sp.words, List: sp.list.name, Amount: sp.amount}
+	if sp.seeded {
+		response.Seed = &sp.seed
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("Failed to encode JSON response: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "encoding_error", "Failed to encode response")
+		return
+	}
+	s.writeCacheable(w, r, "application/json", append(body, '\n'), sp.seeded)
 }
 
-// handleJSON handles the JSON endpoint
-func (s *Server) handleJSON(w http.ResponseWriter, r *http.Request) {
+// speak generates a message from the request's wordlist, amount and seed. On
+// failure it writes an error response and returns false.
+func (s *Server) speak(w http.ResponseWriter, r *http.Request) (speech, bool) {
 	amount, err := s.parseAmount(r)
 	if err != nil {
 		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
-		return
+		return speech{}, false
 	}
 
-	var message string
-	if amount == internal.DefaultAmount {
-		message = s.god.Speak()
+	seed, seeded, err := s.parseSeed(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return speech{}, false
+	}
+
+	list, err := s.parseList(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", err.Error())
+		return speech{}, false
+	}
+
+	var words []string
+	if seeded {
+		words, err = list.god.SpeakSeededWords(amount, seed)
 	} else {
-		message, err = s.god.SpeakWithAmount(amount)
-		if err != nil {
-			s.writeErrorResponse(w, http.StatusBadRequest, "generation_error", err.Error())
-			return
-		}
+		words, err = list.god.SpeakWords(amount)
+	}
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+		return speech{}, false
 	}
 
+	message := strings.Join(words, " ")
 	if message == "" {
 		s.writeErrorResponse(w, http.StatusInternalServerError, "empty_message", "Failed to generate message")
+		return speech{}, false
+	}
+
+	entry := internal.HistoryEntry{Kind: "speak", GodSays: message, Words: words, List: list.name, Amount: amount}
+	if seeded {
+		entry.Seed = &seed
+	}
+	// revalidating a seeded message fetches nothing new, so it was recorded
+	// when the client first got it
+	if !seeded || r.Header.Get("If-None-Match") == "" {
+		s.record(entry)
+	}
+	return speech{message: message, words: words, list: list, amount: amount, seed: seed, seeded: seeded}, true
+}
+
+// handleAsk answers a question posted as JSON or form data
+func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
+	var req AskRequest
+	body := http.MaxBytesReader(w, r.Body, MaxQuestionSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_body", "Failed to decode JSON body")
+			return
+		}
+	} else {
+		r.Body = body
+		if err := r.ParseForm(); err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_body", "Failed to parse form body")
+			return
+		}
+		req.Question = r.PostForm.Get("question")
+		req.Daily, _ = strconv.ParseBool(r.PostForm.Get("daily"))
+	}
+
+	amount, err := s.parseAmount(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
 		return
 	}
+	if req.Amount != 0 {
+		if req.Amount < internal.MinAmount || req.Amount > internal.MaxAmount {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", internal.ErrInvalidAmount.Error())
+			return
+		}
+		amount = req.Amount
+	}
 
-	response := GodResponse{GodSays: message}
+	list, err := s.parseList(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", err.Error())
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.WriteHeader(http.StatusOK)
+	var salt string
+	if req.Daily {
+		salt = internal.DateSalt(s.now().In(s.config.Location))
+	}
+	seed, err := internal.QuestionSeed(req.Question, salt)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_question", err.Error())
+		return
+	}
 
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Failed to encode JSON response: %v", err)
-		s.writeErrorResponse(w, http.StatusInternalServerError, "encoding_error", "Failed to encode response")
+	message, err := list.god.SpeakSeeded(amount, seed)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, "generation_error", err.Error())
+		return
 	}
+
+	// the history is public, so it never keeps what clients asked
+	s.record(internal.HistoryEntry{Kind: "ask", GodSays: message, List: list.name, Amount: amount, Seed: &seed})
+	s.writeJSON(w, http.StatusOK, AskResponse{
+		Question: req.Question,
+		GodSays:  message,
+		List:     list.name,
+		Amount:   amount,
+		Seed:     seed,
+		Date:     salt,
+	})
 }
 
 // handleHealth handles the health check endpoint
@@ -92,3 +203,92 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Failed to encode health response: %v", err)
 	}
 }
+
+// handleListWordlists lists every hosted wordlist
+func (s *Server) handleListWordlists(w http.ResponseWriter, r *http.Request) {
+	lists := s.lists.all()
+	infos := make([]WordlistInfo, 0, len(lists))
+	for _, list := range lists {
+		infos = append(infos, list.info())
+	}
+
+	s.writeJSON(w, http.StatusOK, infos)
+}
+
+// handleGetWordlist returns the statistics of a single wordlist
+func (s *Server) handleGetWordlist(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	list, err := s.lists.get(name)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", fmt.Sprintf("%v: %q", err, name))
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, list.info())
+}
+
+// handlePutWordlist creates or replaces a wordlist from a newline separated body
+func (s *Server) handlePutWordlist(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if err := validateListName(name); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxWordlistSize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			s.writeErrorResponse(w, http.StatusRequestEntityTooLarge, "wordlist_too_large", fmt.Sprintf("wordlist must be at most %d bytes", MaxWordlistSize))
+			return
+		}
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_wordlist", err.Error())
+		return
+	}
+
+	words, err := internal.ParseWords(bytes.NewReader(body))
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_wordlist", err.Error())
+		return
+	}
+
+	list, created, err := s.lists.put(name, words)
+	switch {
+	case errors.Is(err, ErrBuiltinList):
+		s.writeErrorResponse(w, http.StatusForbidden, "builtin_list", err.Error())
+		return
+	case errors.Is(err, internal.ErrEmptyWordlist):
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_wordlist", err.Error())
+		return
+	case err != nil:
+		log.Printf("Failed to store wordlist %q: %v", name, err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to store wordlist")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	s.writeJSON(w, status, list.info())
+}
+
+// handleDeleteWordlist removes a wordlist
+func (s *Server) handleDeleteWordlist(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	err := s.lists.delete(name)
+	switch {
+	case errors.Is(err, ErrUnknownList):
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", fmt.Sprintf("%v: %q", err, name))
+		return
+	case errors.Is(err, ErrBuiltinList):
+		s.writeErrorResponse(w, http.StatusForbidden, "builtin_list", err.Error())
+		return
+	case err != nil:
+		log.Printf("Failed to delete wordlist %q: %v", name, err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to delete wordlist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthCheckTimeout bounds the time a single health check may take
+const HealthCheckTimeout = 5 * time.Second
+
+// ErrDraining is reported by the readiness probe once shutdown has begun
+var ErrDraining = errors.New("server is shutting down")
+
+// HealthCheck is a named probe contributing to liveness or readiness
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// healthChecks holds the registered liveness and readiness checks
+type healthChecks struct {
+	mu        sync.RWMutex
+	liveness  []HealthCheck
+	readiness []HealthCheck
+}
+
+// checkResult is the outcome of a single health check
+type checkResult struct {
+	name string
+	err  error
+}
+
+// AddLivenessCheck registers a check that must pass for /livez to succeed
+func (s *Server) AddLivenessCheck(name string, check func(ctx context.Context) error) {
+	s.checks.mu.Lock()
+	defer s.checks.mu.Unlock()
+	s.checks.liveness = append(s.checks.liveness, HealthCheck{Name: name, Check: check})
+}
+
+// AddReadinessCheck registers a check that must pass for /readyz to succeed
+func (s *Server) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	s.checks.mu.Lock()
+	defer s.checks.mu.Unlock()
+	s.checks.readiness = append(s.checks.readiness, HealthCheck{Name: name, Check: check})
+}
+
+// registerDefaultChecks installs the built-in liveness and readiness checks
+func (s *Server) registerDefaultChecks() {
+	s.AddLivenessCheck("ping", func(ctx context.Context) error { return nil })
+
+	s.AddReadinessCheck("draining", s.checkDraining)
+	s.AddReadinessCheck("wordlists", s.checkWordlists)
+	if s.config.DataDir != "" {
+		s.AddReadinessCheck("storage", s.checkStorage)
+	}
+}
+
+// SetDraining marks the server as shutting down so readiness starts failing
+func (s *Server) SetDraining(draining bool) {
+	s.draining.Store(draining)
+}
+
+// checkDraining fails once the server has started shutting down
+func (s *Server) checkDraining(ctx context.Context) error {
+	if s.draining.Load() {
+		return ErrDraining
+	}
+	return nil
+}
+
+// checkWordlists verifies every hosted wordlist is loaded and non-empty
+func (s *Server) checkWordlists(ctx context.Context) error {
+	lists := s.lists.all()
+	if len(lists) == 0 {
+		return errors.New("no wordlists loaded")
+	}
+	for _, list := range lists {
+		if list.god.GetWordsCount() == 0 {
+			return fmt.Errorf("wordlist %q is empty", list.name)
+		}
+	}
+	return nil
+}
+
+// checkStorage verifies the data directory is writable
+func (s *Server) checkStorage(ctx context.Context) error {
+	f, err := os.CreateTemp(s.config.DataDir, ".healthcheck-*")
+	if err != nil {
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// runChecks executes checks and returns their results in order
+func runChecks(ctx context.Context, checks []HealthCheck) []checkResult {
+	results := make([]checkResult, len(checks))
+	for i, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+		results[i] = checkResult{name: check.Name, err: check.Check(checkCtx)}
+		cancel()
+	}
+	return results
+}
+
+// handleLivez handles the liveness probe
+func (s *Server) handleLivez(w http.ResponseWriter, r *http.Request) {
+	s.checks.mu.RLock()
+	checks := append([]HealthCheck(nil), s.checks.liveness...)
+	s.checks.mu.RUnlock()
+
+	s.writeProbe(w, r, "livez", runChecks(r.Context(), checks))
+}
+
+// handleReadyz handles the readiness probe
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.checks.mu.RLock()
+	checks := append([]HealthCheck(nil), s.checks.readiness...)
+	s.checks.mu.RUnlock()
+
+	s.writeProbe(w, r, "readyz", runChecks(r.Context(), checks))
+}
+
+// writeProbe writes a probe response. With ?verbose every check is listed.
+func (s *Server) writeProbe(w http.ResponseWriter, r *http.Request, probe string, results []checkResult) {
+	_, verbose := r.URL.Query()["verbose"]
+
+	var body strings.Builder
+	failed := false
+	for _, result := range results {
+		if result.err != nil {
+			failed = true
+			fmt.Fprintf(&body, "[-]%s failed: %v\n", result.name, result.err)
+		} else if verbose {
+			fmt.Fprintf(&body, "[+]%s ok\n", result.name)
+		}
+	}
+
+	status := http.StatusOK
+	if failed {
+		status = http.StatusServiceUnavailable
+		fmt.Fprintf(&body, "%s check failed\n", probe)
+	} else if verbose {
+		fmt.Fprintf(&body, "%s check passed\n", probe)
+	} else {
+		body.WriteString("ok\n")
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	fmt.Fprint(w, body.String())
+}
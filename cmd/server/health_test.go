@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func probe(t *testing.T, server *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+	return rr
+}
+
+func TestLivez(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	rr := probe(t, server, "/livez")
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Body.String() != "ok\n" {
+		t.Errorf("Expected body 'ok', got %q", rr.Body.String())
+	}
+
+	// Draining must not affect liveness
+	server.SetDraining(true)
+	if rr := probe(t, server, "/livez"); rr.Code != http.StatusOK {
+		t.Errorf("Expected liveness to pass while draining, got %d", rr.Code)
+	}
+}
+
+func TestReadyzVerbose(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DataDir = t.TempDir()
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	rr := probe(t, server, "/readyz?verbose")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	for _, line := range []string{"[+]draining ok", "[+]wordlists ok", "[+]storage ok", "readyz check passed"} {
+		if !strings.Contains(rr.Body.String(), line) {
+			t.Errorf("Expected verbose output to contain %q, got %q", line, rr.Body.String())
+		}
+	}
+}
+
+func TestReadyzDraining(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	server.SetDraining(true)
+	rr := probe(t, server, "/readyz")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d while draining, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "[-]draining failed") {
+		t.Errorf("Expected draining failure in body, got %q", rr.Body.String())
+	}
+}
+
+func TestReadyzCustomCheck(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	server.AddReadinessCheck("upstream", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	rr := probe(t, server, "/readyz?verbose")
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "[-]upstream failed: connection refused") {
+		t.Errorf("Expected custom check failure in body, got %q", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "[+]wordlists ok") {
+		t.Errorf("Expected other checks to still be listed, got %q", rr.Body.String())
+	}
+}
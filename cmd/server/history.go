@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// record saves a generated message in the history when it is enabled.
+// Failures are logged so they never fail the request.
+func (s *Server) record(entry internal.HistoryEntry) {
+	if s.history == nil {
+		return
+	}
+	entry.Source = "server"
+	if _, err := s.history.Add(entry); err != nil {
+		log.Printf("Failed to record message in history: %v", err)
+	}
+}
+
+// handleHistory returns a page of recorded messages, newest first
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseHistoryQuery(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	// the history is public, so questions recorded in a file shared with
+	// the CLI are neither returned nor searchable
+	query.OmitQuestions = true
+	page, err := s.history.Query(query)
+	if errors.Is(err, internal.ErrInvalidHistoryQuery) {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to query history: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "storage_error", "Failed to read history")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, page)
+}
+
+// parseHistoryQuery parses the search, since, until, offset and limit
+// parameters of a history request
+func (s *Server) parseHistoryQuery(r *http.Request) (internal.HistoryQuery, error) {
+	params := r.URL.Query()
+	query := internal.HistoryQuery{Search: params.Get("search")}
+
+	var err error
+	if value := params.Get("since"); value != "" {
+		if query.Since, err = internal.ParseSince(value, s.now(), s.config.Location); err != nil {
+			return query, err
+		}
+	}
+	if value := params.Get("until"); value != "" {
+		if query.Until, err = internal.ParseSince(value, s.now(), s.config.Location); err != nil {
+			return query, err
+		}
+	}
+	if value := params.Get("offset"); value != "" {
+		if query.Offset, err = strconv.Atoi(value); err != nil {
+			return query, fmt.Errorf("invalid offset parameter: must be a number")
+		}
+	}
+	if value := params.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, fmt.Errorf("invalid limit parameter: must be a number")
+		}
+	}
+	return query, nil
+}
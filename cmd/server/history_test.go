@@ -0,0 +1,179 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+func TestHistoryRecordsMessages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	for _, target := range []string{"/json?amount=3&seed=42", "/?amount=2", "/json?amount=4"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusOK, target, rr.Code)
+		}
+	}
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/ask", strings.NewReader(`{"question": "Will it rain?"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d for /ask, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/history?limit=2", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var page internal.HistoryPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if page.Total != 4 || len(page.Entries) != 2 || page.Limit != 2 {
+		t.Fatalf("Expected the first 2 of 4 entries, got %+v", page)
+	}
+	if ask := page.Entries[0]; ask.Kind != "ask" || ask.GodSays == "" || ask.Source != "server" {
+		t.Errorf("Expected the answer first, got %+v", ask)
+	}
+	if ask := page.Entries[0]; ask.Question != "" {
+		t.Errorf("Expected the question to stay private, got %q", ask.Question)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/history?offset=3", nil))
+	json.Unmarshal(rr.Body.Bytes(), &page)
+	if len(page.Entries) != 1 || page.Entries[0].Seed == nil || *page.Entries[0].Seed != 42 || len(page.Entries[0].Words) != 3 {
+		t.Errorf("Expected the seeded message last, got %+v", page.Entries)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/history?search=RAIN&since=1h", nil))
+	json.Unmarshal(rr.Body.Bytes(), &page)
+	if page.Total != 0 {
+		t.Errorf("Expected questions not to be searchable, got %+v", page.Entries)
+	}
+
+	for _, target := range []string{"/history?limit=1000", "/history?offset=x", "/history?since=someday"} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, target, rr.Code)
+		}
+	}
+}
+
+func TestHistoryBounded(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	cfg.HistoryMaxEntries = 5
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+	total := func() int {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/history", nil))
+		var page internal.HistoryPage
+		if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		return page.Total
+	}
+
+	// revalidating a seeded message is not recorded again
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/?seed=42", nil))
+	for range 3 {
+		req := httptest.NewRequest("GET", "/?seed=42", nil)
+		req.Header.Set("If-None-Match", rr.Header().Get("ETag"))
+		revalidated := httptest.NewRecorder()
+		router.ServeHTTP(revalidated, req)
+		if revalidated.Code != http.StatusNotModified {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotModified, revalidated.Code)
+		}
+	}
+	if n := total(); n != 1 {
+		t.Errorf("Expected 1 recorded message, got %d", n)
+	}
+
+	// the oldest messages are dropped past the limit
+	for range 20 {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	if n := total(); n < 5 || n > 5+5/10 {
+		t.Errorf("Expected about 5 recorded messages, got %d", n)
+	}
+}
+
+func TestHistoryHidesSharedQuestions(t *testing.T) {
+	// a history file shared with the CLI keeps the questions asked there
+	cfg := DefaultConfig()
+	cfg.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	cfg.FavoritesFile = filepath.Join(t.TempDir(), "favorites.jsonl")
+	shared, err := internal.OpenHistory(cfg.HistoryFile)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	entry, err := shared.Add(internal.HistoryEntry{Source: "cli", Kind: "ask", GodSays: "maybe", Amount: 1, Question: "Should I quit my job?"})
+	if err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	for _, target := range []string{"/history", "/history?search=quit"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", target, nil))
+		if strings.Contains(rr.Body.String(), "quit") {
+			t.Errorf("Expected %s to leave out the question, got %s", target, rr.Body.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/favorites", strings.NewReader(`{"history_id": "`+entry.ID+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated || strings.Contains(rr.Body.String(), "quit") {
+		t.Errorf("Expected the blessed favorite to leave out the question, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/m/"+entry.ID+"?format=json", nil))
+	if rr.Code != http.StatusOK || strings.Contains(rr.Body.String(), "quit") {
+		t.Errorf("Expected the permalink to leave out the question, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, httptest.NewRequest("GET", "/history", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d without a history file, got %d", http.StatusNotFound, rr.Code)
+	}
+}
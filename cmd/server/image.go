@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// handlePNG renders the message as a TempleOS style PNG image
+func (s *Server) handlePNG(w http.ResponseWriter, r *http.Request) {
+	s.writeImage(w, r, "image/png", internal.RenderPNG)
+}
+
+// handleSVG renders the message as a TempleOS style SVG image
+func (s *Server) handleSVG(w http.ResponseWriter, r *http.Request) {
+	s.writeImage(w, r, "image/svg+xml", internal.RenderSVG)
+}
+
+// imageRenderer renders a message as an image
+type imageRenderer func(w io.Writer, message string, opts internal.ImageOptions) error
+
+// writeImage generates a message and renders it with render
+func (s *Server) writeImage(w http.ResponseWriter, r *http.Request, contentType string, render imageRenderer) {
+	if _, err := parseImageOptions(r); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	sp, ok := s.speak(w, r)
+	if !ok {
+		return
+	}
+
+	body, ok := s.renderImage(w, r, sp.message, render)
+	if !ok {
+		return
+	}
+	s.writeCacheable(w, r, contentType, body, sp.seeded)
+}
+
+// renderImage renders message with render, configured by the request. On
+// failure it writes an error response and returns false.
+func (s *Server) renderImage(w http.ResponseWriter, r *http.Request, message string, render imageRenderer) ([]byte, bool) {
+	opts, err := parseImageOptions(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, message, opts); err != nil {
+		if errors.Is(err, internal.ErrInvalidImageOptions) {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return nil, false
+		}
+		log.Printf("Failed to render image: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "render_error", "Failed to render image")
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// parseImageOptions parses the width, scale, theme and palette parameters
+func parseImageOptions(r *http.Request) (internal.ImageOptions, error) {
+	opts := internal.DefaultImageOptions()
+	query := r.URL.Query()
+
+	for key, target := range map[string]*int{"width": &opts.Width, "scale": &opts.Scale} {
+		if value := query.Get(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s parameter: must be a number", key)
+			}
+			*target = n
+		}
+	}
+	if theme := query.Get("theme"); theme != "" {
+		opts.Theme = theme
+	}
+	if palette := query.Get("palette"); palette != "" {
+		opts.Palette = palette
+	}
+	return opts, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerImagePNG(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/image.png?width=320&scale=1&theme=dark&palette=rainbow&seed=7", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Expected content type image/png, got %s", ct)
+	}
+	if rr.Header().Get("ETag") == "" {
+		t.Error("Expected seeded images to be cacheable")
+	}
+
+	img, err := png.Decode(bytes.NewReader(rr.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 320 {
+		t.Errorf("Expected width 320, got %d", img.Bounds().Dx())
+	}
+}
+
+func TestServerImageSVG(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/image.svg?amount=3", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Expected content type image/svg+xml, got %s", ct)
+	}
+	if !strings.HasPrefix(rr.Body.String(), "<svg") {
+		t.Errorf("Expected SVG document, got %q", rr.Body.String())
+	}
+}
+
+func TestServerImageInvalidOptions(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, query := range []string{"width=abc", "width=10", "scale=99", "theme=neon", "palette=plaid", "amount=0"} {
+		req, _ := http.NewRequest("GET", "/image.png?"+query, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+}
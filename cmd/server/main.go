@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -21,11 +22,65 @@ const (
 	RequestTimeout = 30 * time.Second
 	// ShutdownTimeout is the maximum time to wait for graceful shutdown
 	ShutdownTimeout = 30 * time.Second
+	// MaxQuestionSize is the maximum accepted size of a question body
+	MaxQuestionSize = 64 << 10
+	// DefaultHistoryMaxEntries is the number of recorded messages kept by
+	// default
+	DefaultHistoryMaxEntries = 10000
 )
 
 // GodResponse represents the JSON response structure
 type GodResponse struct {
-	GodSays string `json:"god_says"`
+	GodSays string   `json:"god_says"`
+	Words   []string `json:"words,omitempty"`
+	List    string   `json:"list"`
+	Amount  int      `json:"amount"`
+	Seed    *int64   `json:"seed,omitempty"`
+}
+
+// AskRequest represents the body of a question posted to /ask
+type AskRequest struct {
+	Question string `json:"question"`
+	// Daily salts the answer with the current date so it changes every day
+	Daily  bool `json:"daily"`
+	Amount int  `json:"amount,omitempty"`
+}
+
+// DayResponse represents the message of the day
+type DayResponse struct {
+	Date      string   `json:"date"`
+	Namespace string   `json:"namespace"`
+	Timezone  string   `json:"timezone"`
+	GodSays   string   `json:"god_says"`
+	Words     []string `json:"words,omitempty"`
+	List      string   `json:"list"`
+	Amount    int      `json:"amount"`
+	Seed      int64    `json:"seed"`
+}
+
+// AskResponse represents the oracle's answer
+type AskResponse struct {
+	Question string `json:"question"`
+	GodSays  string `json:"god_says"`
+	List     string `json:"list"`
+	Amount   int    `json:"amount"`
+	Seed     int64  `json:"seed"`
+	Date     string `json:"date,omitempty"`
+}
+
+// FavoriteRequest represents the body posted to /favorites. It blesses
+// either a message recorded in the history or the message a seed reproduces.
+type FavoriteRequest struct {
+	HistoryID string `json:"history_id,omitempty"`
+	Seed      *int64 `json:"seed,omitempty"`
+	Amount    int    `json:"amount,omitempty"`
+	List      string `json:"list,omitempty"`
+}
+
+// FavoriteResponse represents a blessed message and its permalink
+type FavoriteResponse struct {
+	internal.HistoryEntry
+	URL string `json:"url"`
 }
 
 // ErrorResponse represents an error response structure
@@ -41,23 +96,126 @@ type HealthResponse struct {
 	Uptime     string `json:"uptime"`
 }
 
+// Config holds the server configuration
+type Config struct {
+	Host string
+	Port int
+	// DataDir is where uploaded wordlists are persisted; empty keeps them in memory
+	DataDir string
+	// AdminToken protects the /admin routes; empty disables them
+	AdminToken string
+
+	CORS            CORSConfig
+	SecurityHeaders SecurityHeadersConfig
+
+	// AdminAddr is the address of the pprof/expvar listener; empty disables it
+	AdminAddr string
+
+	// Location is the timezone deciding which day it is; nil means UTC
+	Location *time.Location
+	// Namespace distinguishes the message of the day between deployments
+	Namespace string
+
+	// BaseURL is the public URL used in feed links and permalinks; empty
+	// derives it from the Host header and keeps those responses private
+	BaseURL string
+
+	// DrainDelay is how long readiness fails before shutdown after SIGTERM
+	DrainDelay time.Duration
+
+	// HistoryFile records every generated message and enables GET /history;
+	// empty disables both
+	HistoryFile string
+	// HistoryMaxEntries caps the recorded messages, dropping the oldest
+	// first; 0 keeps every message
+	HistoryMaxEntries int
+	// FavoritesFile stores messages blessed with POST /favorites and enables
+	// their permalinks at /m/{id}; empty disables both. With an AdminToken
+	// only admins may bless messages.
+	FavoritesFile string
+}
+
+// DefaultConfig returns the default server configuration
+func DefaultConfig() Config {
+	return Config{
+		Host:            "127.0.0.1",
+		Port:            3333,
+		CORS:            DefaultCORSConfig(),
+		SecurityHeaders: DefaultSecurityHeadersConfig(),
+		DrainDelay:      5 * time.Second,
+		Location:        time.UTC,
+		Namespace:       internal.DefaultNamespace,
+
+		HistoryMaxEntries: DefaultHistoryMaxEntries,
+	}
+}
+
 // Server holds the server state and dependencies
 type Server struct {
 	god       *internal.God
+	lists     *wordlistRegistry
+	config    Config
 	startTime time.Time
+	checks    healthChecks
+	// history records generated messages; nil when disabled
+	history *internal.History
+	// favorites stores blessed messages; nil when disabled
+	favorites *internal.History
+	draining  atomic.Bool
+	// now is the clock used for date based messages
+	now func() time.Time
 }
 
-// NewServer creates a new server instance
+// NewServer creates a new server instance with the default configuration
 func NewServer() (*Server, error) {
+	return NewServerWithConfig(DefaultConfig())
+}
+
+// NewServerWithConfig creates a new server instance
+func NewServerWithConfig(cfg Config) (*Server, error) {
 	god, err := internal.NewGod(internal.DefaultAmount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create god instance: %w", err)
 	}
 
-	return &Server{
+	if err := cfg.CORS.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = internal.DefaultNamespace
+	}
+
+	lists, err := newWordlistRegistry(god, cfg.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wordlists: %w", err)
+	}
+
+	server := &Server{
 		god:       god,
+		lists:     lists,
+		config:    cfg,
 		startTime: time.Now(),
-	}, nil
+		now:       time.Now,
+	}
+	if cfg.HistoryFile != "" {
+		if server.history, err = internal.OpenHistory(cfg.HistoryFile); err != nil {
+			return nil, err
+		}
+		if err := server.history.SetMaxEntries(cfg.HistoryMaxEntries); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.FavoritesFile != "" {
+		if server.favorites, err = internal.OpenHistory(cfg.FavoritesFile); err != nil {
+			return nil, err
+		}
+	}
+	server.registerDefaultChecks()
+
+	return server, nil
 }
 
 // writeErrorResponse writes a JSON error response
@@ -74,6 +232,16 @@ func (s *Server) writeErrorResponse(w http.ResponseWriter, statusCode int, error
 	}
 }
 
+// writeJSON writes v as a JSON response
+func (s *Server) writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to encode JSON response: %v", err)
+	}
+}
+
 // parseAmount parses and validates the amount parameter from request
 func (s *Server) parseAmount(r *http.Request) (int, error) {
 	amountStr := r.URL.Query().Get("amount")
@@ -92,34 +260,124 @@ func (s *Server) parseAmount(r *http.Request) (int, error) {
 	return amount, nil
 }
 
-// RunServer starts the HTTP server
-func RunServer(host string, port int) error {
-	addr := fmt.Sprintf("%s:%d", host, port)
+// parseSeed parses the optional seed parameter. A seed makes the response
+// deterministic.
+func (s *Server) parseSeed(r *http.Request) (int64, bool, error) {
+	seedStr := r.URL.Query().Get("seed")
+	if seedStr == "" {
+		return 0, false, nil
+	}
 
-	server, err := NewServer()
+	seed, err := strconv.ParseInt(seedStr, 10, 64)
 	if err != nil {
-		return err
+		return 0, false, fmt.Errorf("invalid seed parameter: must be a 64-bit integer")
+	}
+	return seed, true, nil
+}
+
+// parseList resolves the wordlist selected by the list parameter
+func (s *Server) parseList(r *http.Request) (*wordlist, error) {
+	name := r.URL.Query().Get("list")
+	if name == "" {
+		name = DefaultList
+	}
+
+	list, err := s.lists.get(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, name)
 	}
+	return list, nil
+}
 
+// routes builds the router with all endpoints and middlewares
+func (s *Server) routes() *mux.Router {
 	r := mux.NewRouter()
-	r.HandleFunc("/", server.handleRoot).Methods("GET", "OPTIONS")
-	r.HandleFunc("/json", server.handleJSON).Methods("GET", "OPTIONS")
-	r.HandleFunc("/health", server.handleHealth).Methods("GET", "OPTIONS")
+	r.HandleFunc("/", s.handleRoot).Methods("GET", "OPTIONS")
+	r.HandleFunc("/json", s.handleJSON).Methods("GET", "OPTIONS")
+	r.HandleFunc("/image.png", s.handlePNG).Methods("GET", "OPTIONS")
+	r.HandleFunc("/image.svg", s.handleSVG).Methods("GET", "OPTIONS")
+	r.HandleFunc("/song.wav", s.handleSong).Methods("GET", "OPTIONS")
+	r.HandleFunc("/qr.png", s.handleQRPNG).Methods("GET", "OPTIONS")
+	r.HandleFunc("/qr.svg", s.handleQRSVG).Methods("GET", "OPTIONS")
+	r.HandleFunc("/qr.txt", s.handleQRText).Methods("GET", "OPTIONS")
+	r.HandleFunc("/today", s.handleToday).Methods("GET", "OPTIONS")
+	r.HandleFunc("/day/{date:[0-9]{4}-[0-9]{2}-[0-9]{2}}", s.handleDay).Methods("GET", "OPTIONS")
+	r.HandleFunc("/feed.rss", s.handleRSS).Methods("GET", "OPTIONS")
+	r.HandleFunc("/feed.atom", s.handleAtom).Methods("GET", "OPTIONS")
+	r.HandleFunc("/calendar.ics", s.handleCalendar).Methods("GET", "OPTIONS")
+	r.HandleFunc("/ask", s.handleAsk).Methods("POST", "OPTIONS")
+	r.HandleFunc("/health", s.handleHealth).Methods("GET", "OPTIONS")
+	r.HandleFunc("/livez", s.handleLivez).Methods("GET")
+	r.HandleFunc("/readyz", s.handleReadyz).Methods("GET")
+	if s.history != nil {
+		r.HandleFunc("/history", s.handleHistory).Methods("GET", "OPTIONS")
+	}
+	if s.favorites != nil {
+		addFavorite := http.Handler(http.HandlerFunc(s.handleAddFavorite))
+		if s.config.AdminToken != "" {
+			// every blessing grows the favorites file, so admins may restrict it
+			addFavorite = adminAuthMiddleware(s.config.AdminToken)(addFavorite)
+		}
+		r.Handle("/favorites", addFavorite).Methods("POST", "OPTIONS")
+		r.HandleFunc("/m/{id}", s.handleFavorite).Methods("GET", "OPTIONS")
+	}
+
+	if s.config.AdminToken != "" {
+		admin := r.PathPrefix("/admin").Subrouter()
+		admin.HandleFunc("/lists", s.handleListWordlists).Methods("GET")
+		admin.HandleFunc("/lists/{name}", s.handleGetWordlist).Methods("GET")
+		admin.HandleFunc("/lists/{name}", s.handlePutWordlist).Methods("PUT")
+		admin.HandleFunc("/lists/{name}", s.handleDeleteWordlist).Methods("DELETE")
+		admin.Use(adminAuthMiddleware(s.config.AdminToken))
+	}
 
 	// Add middlewares
 	r.Use(loggingMiddleware)
-	r.Use(securityMiddleware)
+	r.Use(securityMiddleware(s.config.SecurityHeaders))
+	r.Use(corsMiddleware(s.config.CORS))
 	r.Use(timeoutMiddleware(RequestTimeout))
 
+	return r
+}
+
+// RunServer starts the HTTP server
+func RunServer(cfg Config) error {
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
 	// Create HTTP server with timeouts
 	httpServer := &http.Server{
 		Addr:         addr,
-		Handler:      r,
+		Handler:      server.routes(),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	var adminServer *http.Server
+	if cfg.AdminAddr != "" {
+		adminServer = &http.Server{
+			Addr:        cfg.AdminAddr,
+			Handler:     server.debugRoutes(),
+			ReadTimeout: 15 * time.Second,
+			IdleTimeout: 60 * time.Second,
+		}
+		go func() {
+			log.Printf("Debug endpoints on %s: /debug/pprof/ /debug/vars /debug/buildinfo", cfg.AdminAddr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start admin server: %v", err)
+			}
+		}()
+	}
+
+	if server.favorites != nil && cfg.AdminToken == "" {
+		log.Printf("Warning: anyone can bless messages with POST /favorites; set an admin token to restrict it")
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -128,21 +386,62 @@ func RunServer(host string, port int) error {
 		log.Printf("Endpoints:")
 		log.Printf("  GET /        - Plain text response")
 		log.Printf("  GET /json    - JSON response")
+		log.Printf("  GET /image.png, /image.svg - TempleOS style image (?width=&scale=&theme=&palette=)")
+		log.Printf("  GET /song.wav - God song (?tempo=&length=&seed=&waveform=&from=random|message)")
+		log.Printf("  GET /qr.png, /qr.svg, /qr.txt - QR code of a message or its permalink (?level=L|M|Q|H&scale=&content=message|link)")
+		log.Printf("  GET /today   - Message of the day (%s, namespace %q)", cfg.Location, cfg.Namespace)
+		log.Printf("  GET /day/{YYYY-MM-DD} - Message of a given day")
+		log.Printf("  GET /feed.rss, /feed.atom - Feeds of daily messages (?days=N)")
+		log.Printf("  GET /calendar.ics - Calendar of upcoming daily messages (?days=N)")
+		log.Printf("  POST /ask    - Ask God a question")
 		log.Printf("  GET /health  - Health check")
+		log.Printf("  GET /livez   - Liveness probe (?verbose)")
+		log.Printf("  GET /readyz  - Readiness probe (?verbose)")
+		if server.history != nil {
+			log.Printf("  GET /history - Recorded messages (?search=&since=&until=&offset=&limit=)")
+		}
+		if server.favorites != nil {
+			if cfg.AdminToken != "" {
+				log.Printf("  POST /favorites - Bless a recorded or seeded message (admin token required)")
+			} else {
+				log.Printf("  POST /favorites - Bless a recorded or seeded message (open to anyone)")
+			}
+			log.Printf("  GET /m/{id}  - Permalink of a blessed message (?format=text|ascii|json|png|svg|wav|qr.png|qr.svg|qr.txt)")
+		}
+		if cfg.AdminToken != "" {
+			log.Printf("  GET|PUT|DELETE /admin/lists[/{name}] - Wordlist administration")
+		}
 
-		if err := httpServer.ListenAndServe(); err != nil {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
 	// Wait for interrupt signal
-	<-stop
+	sig := <-stop
+
+	// Fail readiness first so load balancers stop routing new traffic
+	server.SetDraining(true)
+	if sig == syscall.SIGTERM && cfg.DrainDelay > 0 {
+		log.Printf("Draining for %v before shutdown, signal again to skip...", cfg.DrainDelay)
+		select {
+		case <-time.After(cfg.DrainDelay):
+		case <-stop:
+			log.Println("Skipping drain")
+		}
+	}
 	log.Println("Shutting down server...")
 
 	// Create a context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
 	defer cancel()
 
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Printf("Admin server forced to shutdown: %v", err)
+		}
+	}
+
 	// Attempt graceful shutdown
 	if err := httpServer.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
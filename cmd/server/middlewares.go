@@ -1,8 +1,15 @@
 package server
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,34 +22,154 @@ func loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// securityMiddleware adds security headers
-func securityMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Security headers
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-		w.Header().Set("X-Frame-Options", "DENY")
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
-
-		// CORS headers
-		origin := r.Header.Get("Origin")
-		if origin != "" {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-		} else {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-		}
+// SecurityHeadersConfig configures the security headers added to every
+// response. Empty values omit the corresponding header.
+type SecurityHeadersConfig struct {
+	ContentTypeOptions      string
+	FrameOptions            string
+	XSSProtection           string
+	ReferrerPolicy          string
+	ContentSecurityPolicy   string
+	StrictTransportSecurity string
+}
+
+// DefaultSecurityHeadersConfig returns the default security headers
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		ContentTypeOptions:    "nosniff",
+		FrameOptions:          "DENY",
+		XSSProtection:         "1; mode=block",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+	}
+}
+
+// CORSConfig configures the cross-origin resource sharing policy
+type CORSConfig struct {
+	// AllowedOrigins lists the permitted origins. "*" allows any origin and
+	// patterns such as "https://*.example.com" match any subdomain.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// ErrCredentialedWildcard is returned for a CORS policy allowing any origin
+// with credentials
+var ErrCredentialedWildcard = errors.New(`CORS origin "*" cannot be combined with credentials`)
+
+// DefaultCORSConfig returns the default CORS policy allowing any origin
+// without credentials
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         24 * time.Hour,
+	}
+}
 
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("Access-Control-Max-Age", "86400")
+// validate rejects policies that would let any origin send credentials
+func (c CORSConfig) validate() error {
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		return ErrCredentialedWildcard
+	}
+	return nil
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// allowsOrigin reports whether origin matches one of the allowed origins.
+// With credentials the "*" entry matches nothing, so an origin is never
+// reflected just because any origin is allowed.
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range c.AllowedOrigins {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" {
+			if c.AllowCredentials {
+				continue
+			}
+			return true
+		}
+		if pattern == origin {
+			return true
+		}
+		if ok, err := path.Match(pattern, origin); err == nil && ok {
+			return true
 		}
+	}
+	return false
+}
 
-		next.ServeHTTP(w, r)
-	})
+// allowsAnyOrigin reports whether the policy is a plain wildcard
+func (c CORSConfig) allowsAnyOrigin() bool {
+	return !c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*")
+}
+
+// securityMiddleware adds security headers
+func securityMiddleware(cfg SecurityHeadersConfig) func(http.Handler) http.Handler {
+	headers := map[string]string{
+		"X-Content-Type-Options":    cfg.ContentTypeOptions,
+		"X-Frame-Options":           cfg.FrameOptions,
+		"X-XSS-Protection":          cfg.XSSProtection,
+		"Referrer-Policy":           cfg.ReferrerPolicy,
+		"Content-Security-Policy":   cfg.ContentSecurityPolicy,
+		"Strict-Transport-Security": cfg.StrictTransportSecurity,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for header, value := range headers {
+				if value != "" {
+					w.Header().Set(header, value)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// corsMiddleware applies the CORS policy and answers preflight requests
+func corsMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if !cfg.allowsAnyOrigin() {
+				w.Header().Add("Vary", "Origin")
+			}
+
+			if origin != "" && cfg.allowsOrigin(origin) {
+				if cfg.allowsAnyOrigin() {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+				if cfg.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+
+				if r.Method == "OPTIONS" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+					if cfg.MaxAge > 0 {
+						w.Header().Set("Access-Control-Max-Age", maxAge)
+					}
+				}
+			}
+
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 // timeoutMiddleware adds request timeout
@@ -51,3 +178,23 @@ func timeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
 		return http.TimeoutHandler(next, timeout, "Request timeout")
 	}
 }
+
+// adminAuthMiddleware requires a matching bearer token on admin routes
+func adminAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("WWW-Authenticate", `Bearer realm="godsays-admin"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				if err := json.NewEncoder(w).Encode(ErrorResponse{Error: "unauthorized", Message: "A valid admin token is required"}); err != nil {
+					log.Printf("Failed to encode error response: %v", err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// handleQRPNG serves a QR code of a message as a PNG image
+func (s *Server) handleQRPNG(w http.ResponseWriter, r *http.Request) {
+	s.writeQR(w, r, "image/png", func(q *internal.QRCode, w io.Writer, scale int) error {
+		return q.WritePNG(w, scale)
+	})
+}
+
+// handleQRSVG serves a QR code of a message as an SVG image
+func (s *Server) handleQRSVG(w http.ResponseWriter, r *http.Request) {
+	s.writeQR(w, r, "image/svg+xml", func(q *internal.QRCode, w io.Writer, scale int) error {
+		return q.WriteSVG(w, scale)
+	})
+}
+
+// handleQRText serves a QR code of a message drawn with block characters for
+// terminals; ?invert=true suits light backgrounds
+func (s *Server) handleQRText(w http.ResponseWriter, r *http.Request) {
+	invert, err := parseInvert(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	s.writeQR(w, r, "text/plain; charset=utf-8", func(q *internal.QRCode, w io.Writer, _ int) error {
+		return q.WriteTerminal(w, invert)
+	})
+}
+
+// qrRenderer renders an encoded QR code with scale pixels per module
+type qrRenderer func(q *internal.QRCode, w io.Writer, scale int) error
+
+// writeQR encodes the message, or with ?content=link a permalink that
+// reproduces it, as a QR code and renders it
+func (s *Server) writeQR(w http.ResponseWriter, r *http.Request, contentType string, render qrRenderer) {
+	if _, _, err := parseQROptions(r); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	var (
+		data          string
+		deterministic bool
+	)
+	switch content := r.URL.Query().Get("content"); content {
+	case "", "message":
+		sp, ok := s.speak(w, r)
+		if !ok {
+			return
+		}
+		data, deterministic = sp.message, sp.seeded
+	case "link":
+		link, seeded, ok := s.permalink(w, r)
+		if !ok {
+			return
+		}
+		data, deterministic = link, seeded
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("content must be message or link, got %q", content))
+		return
+	}
+
+	body, ok := s.renderQR(w, r, data, render)
+	if !ok {
+		return
+	}
+	s.writeCacheable(w, r, contentType, body, deterministic)
+}
+
+// renderQR encodes data as a QR code and renders it, configured by the
+// request. On failure it writes an error response and returns false.
+func (s *Server) renderQR(w http.ResponseWriter, r *http.Request, data string, render qrRenderer) ([]byte, bool) {
+	level, scale, err := parseQROptions(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return nil, false
+	}
+
+	q, err := internal.EncodeQR([]byte(data), level)
+	if err != nil {
+		if errors.Is(err, internal.ErrQRTooLong) {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error()+": lower the amount or the error correction level")
+			return nil, false
+		}
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	if err := render(q, &buf, scale); err != nil {
+		log.Printf("Failed to render QR code: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "render_error", "Failed to render QR code")
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// permalink returns a link to the message selected by the request. Without
+// a seed a random one is chosen, so the link still reproduces one message.
+// On failure it writes an error response and returns false.
+func (s *Server) permalink(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	if _, err := s.parseAmount(r); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return "", false, false
+	}
+	if _, err := s.parseList(r); err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, "unknown_list", err.Error())
+		return "", false, false
+	}
+	seed, seeded, err := s.parseSeed(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return "", false, false
+	}
+	if !seeded {
+		seed = rand.Int63()
+	}
+
+	query := messageQuery(r)
+	query.Set("seed", strconv.FormatInt(seed, 10))
+	return s.baseURL(w, r) + "/?" + query.Encode(), seeded, true
+}
+
+// parseQROptions parses the level and scale parameters
+func parseQROptions(r *http.Request) (internal.QRLevel, int, error) {
+	query := r.URL.Query()
+
+	level := internal.QRMedium
+	if value := query.Get("level"); value != "" {
+		var err error
+		if level, err = internal.ParseQRLevel(value); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	scale := internal.DefaultQRScale
+	if value := query.Get("scale"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid scale parameter: must be a number")
+		}
+		if n < 1 || n > internal.MaxQRScale {
+			return 0, 0, fmt.Errorf("scale must be between 1 and %d", internal.MaxQRScale)
+		}
+		scale = n
+	}
+	return level, scale, nil
+}
+
+// parseInvert parses the invert parameter of terminal QR codes
+func parseInvert(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("invert")
+	if value == "" {
+		return false, nil
+	}
+	invert, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid invert parameter: must be a boolean")
+	}
+	return invert, nil
+}
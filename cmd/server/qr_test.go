@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerQR(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	testCases := []struct {
+		target      string
+		contentType string
+	}{
+		{"/qr.png?amount=5&seed=1&scale=2&level=H", "image/png"},
+		{"/qr.svg?amount=5&seed=1", "image/svg+xml"},
+		{"/qr.txt?amount=5&seed=1&invert=true", "text/plain; charset=utf-8"},
+	}
+	for _, tc := range testCases {
+		req, _ := http.NewRequest("GET", tc.target, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, tc.target, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Errorf("Expected content type %s for %s, got %s", tc.contentType, tc.target, ct)
+		}
+		if rr.Header().Get("ETag") == "" {
+			t.Errorf("Expected seeded QR codes to be cacheable for %s", tc.target)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/qr.png?amount=5&seed=1&scale=2", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+	img, err := png.Decode(bytes.NewReader(rr.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+	if size := img.Bounds().Dx(); size%2 != 0 || (size/2-8-17)%4 != 0 {
+		t.Errorf("Expected a QR code with a quiet zone, got width %d", size)
+	}
+}
+
+func TestServerQRLink(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BaseURL = "https://godsays.example.com"
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/qr.txt?content=link&amount=5", nil)
+	rr := httptest.NewRecorder()
+	server.routes().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if rr.Header().Get("ETag") != "" {
+		t.Error("Expected unseeded permalinks not to be cached")
+	}
+
+	link, seeded, ok := server.permalink(httptest.NewRecorder(), req)
+	if !ok || seeded {
+		t.Fatalf("Expected an unseeded permalink, got ok=%v seeded=%v", ok, seeded)
+	}
+	if !strings.HasPrefix(link, "https://godsays.example.com/?amount=5&seed=") {
+		t.Errorf("Expected a permalink with a random seed, got %q", link)
+	}
+
+	req, _ = http.NewRequest("GET", "/qr.png?content=link&seed=42&list=happy", nil)
+	if link, seeded, _ = server.permalink(httptest.NewRecorder(), req); link != "https://godsays.example.com/?list=happy&seed=42" || !seeded {
+		t.Errorf("Expected the requested seed in the permalink, got %q", link)
+	}
+}
+
+func TestServerQRInvalid(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, target := range []string{"/qr.png?level=X", "/qr.png?scale=0", "/qr.png?scale=x", "/qr.png?content=poem", "/qr.txt?invert=maybe", "/qr.png?amount=1000&level=H"} {
+		req, _ := http.NewRequest("GET", target, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, target, rr.Code)
+		}
+	}
+}
@@ -2,9 +2,15 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -171,6 +177,9 @@ func TestServerHandleJSONWithAmount(t *testing.T) {
 	if len(strings.TrimSpace(response.GodSays)) == 0 {
 		t.Error("Expected non-empty god_says field")
 	}
+	if len(response.Words) != 3 || strings.Join(response.Words, " ") != response.GodSays {
+		t.Errorf("Expected the 3 words of the message, got %q", response.Words)
+	}
 }
 
 func TestServerHandleHealth(t *testing.T) {
@@ -218,7 +227,7 @@ func TestServerMiddleware(t *testing.T) {
 
 	r := mux.NewRouter()
 	r.HandleFunc("/", server.handleRoot).Methods("GET", "OPTIONS")
-	r.Use(securityMiddleware)
+	r.Use(securityMiddleware(DefaultSecurityHeadersConfig()))
 
 	req, err := http.NewRequest("GET", "/", nil)
 	if err != nil {
@@ -230,10 +239,11 @@ func TestServerMiddleware(t *testing.T) {
 
 	// Check security headers
 	securityHeaders := map[string]string{
-		"X-Content-Type-Options": "nosniff",
-		"X-Frame-Options":        "DENY",
-		"X-XSS-Protection":       "1; mode=block",
-		"Referrer-Policy":        "strict-origin-when-cross-origin",
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"X-XSS-Protection":        "1; mode=block",
+		"Referrer-Policy":         "strict-origin-when-cross-origin",
+		"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
 	}
 
 	for header, expectedValue := range securityHeaders {
@@ -251,13 +261,15 @@ func TestServerCORS(t *testing.T) {
 
 	r := mux.NewRouter()
 	r.HandleFunc("/", server.handleRoot).Methods("GET", "OPTIONS")
-	r.Use(securityMiddleware)
+	r.Use(corsMiddleware(DefaultCORSConfig()))
 
 	// Test OPTIONS request
 	req, err := http.NewRequest("OPTIONS", "/", nil)
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
 
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
@@ -278,6 +290,103 @@ func TestServerCORS(t *testing.T) {
 			t.Errorf("Expected %s header to be present", header)
 		}
 	}
+
+	if value := rr.Header().Get("Access-Control-Allow-Origin"); value != "*" {
+		t.Errorf("Expected wildcard origin, got '%s'", value)
+	}
+}
+
+func TestServerCORSPolicy(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins:   []string{"https://*.example.com", "http://localhost:8080"},
+		AllowedMethods:   []string{"GET"},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+	handler := corsMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	testCases := map[string]bool{
+		"https://app.example.com":  true,
+		"https://APP.example.com":  true,
+		"http://localhost:8080":    true,
+		"https://example.com":      false,
+		"https://a.b.example.com":  true,
+		"https://evil.com":         false,
+		"http://app.example.com":   false,
+		"https://example.com.evil": false,
+	}
+	for origin, allowed := range testCases {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Origin", origin)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		got := rr.Header().Get("Access-Control-Allow-Origin")
+		if allowed && got != origin {
+			t.Errorf("Expected origin %s to be reflected, got '%s'", origin, got)
+		}
+		if !allowed && got != "" {
+			t.Errorf("Expected origin %s to be rejected, got '%s'", origin, got)
+		}
+		if allowed && rr.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("Expected credentials to be allowed for %s", origin)
+		}
+		if rr.Header().Get("Vary") != "Origin" {
+			t.Errorf("Expected Vary: Origin for %s, got '%s'", origin, rr.Header().Get("Vary"))
+		}
+	}
+
+	// a credentialed policy never reflects an origin through "*"
+	cfg.AllowedOrigins = append(cfg.AllowedOrigins, "*")
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	rr := httptest.NewRecorder()
+	corsMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected https://evil.com to be rejected with credentials, got '%s'", got)
+	}
+	serverCfg := DefaultConfig()
+	serverCfg.CORS = cfg
+	if _, err := NewServerWithConfig(serverCfg); !errors.Is(err, ErrCredentialedWildcard) {
+		t.Errorf("Expected ErrCredentialedWildcard, got %v", err)
+	}
+
+	req, _ = http.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if value := rr.Header().Get("Access-Control-Max-Age"); value != "3600" {
+		t.Errorf("Expected max age 3600, got '%s'", value)
+	}
+	if value := rr.Header().Get("Access-Control-Allow-Methods"); value != "GET" {
+		t.Errorf("Expected allowed methods 'GET', got '%s'", value)
+	}
+}
+
+func TestServerSecurityHeadersConfig(t *testing.T) {
+	cfg := DefaultSecurityHeadersConfig()
+	cfg.ContentSecurityPolicy = "default-src 'self'"
+	cfg.FrameOptions = ""
+	handler := securityMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if value := rr.Header().Get("Content-Security-Policy"); value != "default-src 'self'" {
+		t.Errorf("Expected configured CSP, got '%s'", value)
+	}
+	if _, ok := rr.Header()["X-Frame-Options"]; ok {
+		t.Error("Expected empty X-Frame-Options to be omitted")
+	}
 }
 
 func TestParseAmount(t *testing.T) {
@@ -328,7 +437,8 @@ func TestServerIntegration(t *testing.T) {
 	r.HandleFunc("/json", server.handleJSON).Methods("GET", "OPTIONS")
 	r.HandleFunc("/health", server.handleHealth).Methods("GET")
 	r.Use(loggingMiddleware)
-	r.Use(securityMiddleware)
+	r.Use(securityMiddleware(DefaultSecurityHeadersConfig()))
+	r.Use(corsMiddleware(DefaultCORSConfig()))
 
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
@@ -369,3 +479,160 @@ func TestServerTimeout(t *testing.T) {
 		t.Errorf("Expected status %d for timeout, got %d", http.StatusServiceUnavailable, status)
 	}
 }
+
+func TestServerHandleAsk(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	r := server.routes()
+
+	ask := func(contentType, body string) AskResponse {
+		req, err := http.NewRequest("POST", "/ask?amount=8", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var response AskResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		return response
+	}
+
+	jsonAnswer := ask("application/json", `{"question": "Is TempleOS divine?"}`)
+	formAnswer := ask("application/x-www-form-urlencoded", "question=is+templeos+divine")
+	if jsonAnswer.GodSays == "" || jsonAnswer.GodSays != formAnswer.GodSays {
+		t.Errorf("Expected the same answer for equivalent questions, got %q and %q", jsonAnswer.GodSays, formAnswer.GodSays)
+	}
+	if jsonAnswer.Amount != 8 || jsonAnswer.Date != "" {
+		t.Errorf("Unexpected answer metadata: %+v", jsonAnswer)
+	}
+
+	if seed, _ := internal.QuestionSeed("Is TempleOS divine?", ""); jsonAnswer.Seed != seed {
+		t.Errorf("Expected the question's seed %d, got %d", seed, jsonAnswer.Seed)
+	}
+	if expected, _ := server.god.SpeakSeeded(8, jsonAnswer.Seed); jsonAnswer.GodSays != expected {
+		t.Errorf("Expected seed %d to reproduce the answer %q, got %q", jsonAnswer.Seed, expected, jsonAnswer.GodSays)
+	}
+
+	daily := ask("application/json", `{"question": "Is TempleOS divine?", "daily": true, "amount": 3}`)
+	if daily.Date == "" || daily.Amount != 3 {
+		t.Errorf("Expected dated answer with amount 3, got %+v", daily)
+	}
+	if expected, _ := server.god.SpeakSeeded(3, daily.Seed); daily.GodSays != expected {
+		t.Errorf("Expected seed %d to reproduce the daily answer %q, got %q", daily.Seed, expected, daily.GodSays)
+	}
+}
+
+func TestServerHandleAskInvalid(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	r := server.routes()
+
+	testCases := []struct {
+		contentType, body string
+	}{
+		{"application/json", `{"question": "  "}`},
+		{"application/json", `{"question": `},
+		{"application/json", `{"question": "why?", "amount": 5000}`},
+		{"application/x-www-form-urlencoded", ""},
+	}
+	for _, tc := range testCases {
+		req, _ := http.NewRequest("POST", "/ask", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for body %q, got %d", http.StatusBadRequest, tc.body, rr.Code)
+		}
+	}
+}
+
+func FuzzQueryParameters(f *testing.F) {
+	f.Add("amount=10&seed=42&list=happy")
+	f.Add("amount=0&seed=9223372036854775808")
+	f.Add("format=ascii&width=8&character=temple&border=ascii&banner=true&amount=1")
+	f.Add("level=H&scale=40&invert=yes&content=link")
+	f.Add("search=rain&since=7d&until=2024-12-25&offset=-1&limit=101")
+	f.Add("days=366&tempo=0&waveform=saw&theme=%00&palette=rainbow")
+	f.Add("amount=%zz;seed=1&&=&amount=2")
+
+	// request logs would flood the fuzzing workers' output
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	server, err := NewServer()
+	if err != nil {
+		f.Fatalf("Failed to create server: %v", err)
+	}
+	router := server.routes()
+
+	f.Fuzz(func(t *testing.T, rawQuery string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.URL.RawQuery = rawQuery
+		reparse := func(query url.Values) *http.Request {
+			again := httptest.NewRequest("GET", "/", nil)
+			again.URL.RawQuery = query.Encode()
+			return again
+		}
+
+		if amount, err := server.parseAmount(req); err == nil {
+			if amount < internal.MinAmount || amount > internal.MaxAmount {
+				t.Fatalf("Expected an amount between %d and %d, got %d", internal.MinAmount, internal.MaxAmount, amount)
+			}
+			if again, err := server.parseAmount(reparse(url.Values{"amount": {strconv.Itoa(amount)}})); err != nil || again != amount {
+				t.Fatalf("Expected amount %d to round-trip, got %d, %v", amount, again, err)
+			}
+		}
+		if seed, seeded, err := server.parseSeed(req); err == nil && seeded {
+			if again, _, err := server.parseSeed(reparse(url.Values{"seed": {strconv.FormatInt(seed, 10)}})); err != nil || again != seed {
+				t.Fatalf("Expected seed %d to round-trip, got %d, %v", seed, again, err)
+			}
+		}
+		if days, err := parseDays(req, DefaultFeedDays); err == nil && (days < 1 || days > MaxFeedDays) {
+			t.Fatalf("Expected days between 1 and %d, got %d", MaxFeedDays, days)
+		}
+		if _, scale, err := parseQROptions(req); err == nil && (scale < 1 || scale > internal.MaxQRScale) {
+			t.Fatalf("Expected a QR scale between 1 and %d, got %d", internal.MaxQRScale, scale)
+		}
+		parseInvert(req)
+		parseASCIIOptions(req)
+		parseImageOptions(req)
+		parseSongOptions(req)
+
+		if query, err := server.parseHistoryQuery(req); err == nil {
+			values := url.Values{"search": {query.Search}, "offset": {strconv.Itoa(query.Offset)}, "limit": {strconv.Itoa(query.Limit)}}
+			if !query.Since.IsZero() {
+				values.Set("since", query.Since.Format(time.RFC3339Nano))
+			}
+			if !query.Until.IsZero() {
+				values.Set("until", query.Until.Format(time.RFC3339Nano))
+			}
+			again, err := server.parseHistoryQuery(reparse(values))
+			if err != nil || again.Search != query.Search || !again.Since.Equal(query.Since) || !again.Until.Equal(query.Until) || again.Offset != query.Offset || again.Limit != query.Limit {
+				t.Fatalf("Expected %+v to round-trip, got %+v, %v", query, again, err)
+			}
+		}
+
+		// bad parameters are the client's fault, never the server's. QR codes
+		// are left out, encoding them is too slow for fuzzing.
+		for _, path := range []string{"/", "/json", "/today", "/feed.atom"} {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", path, nil)
+			req.URL.RawQuery = rawQuery
+			router.ServeHTTP(rr, req)
+			if rr.Code >= 500 {
+				t.Fatalf("Expected a client error for %s?%s, got %d: %s", path, rawQuery, rr.Code, rr.Body.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// handleSong synthesizes a God song as a WAV file. By default the melody is
+// random; with ?from=message it sings the words of a generated message.
+func (s *Server) handleSong(w http.ResponseWriter, r *http.Request) {
+	opts, length, err := parseSongOptions(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	var (
+		notes         []internal.Note
+		deterministic bool
+	)
+	switch from := r.URL.Query().Get("from"); from {
+	case "", "random":
+		seed, seeded, err := s.parseSeed(r)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		if !seeded {
+			seed = rand.Int63()
+		}
+		notes, err = internal.GodSong(seed, length)
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return
+		}
+		deterministic = seeded
+	case "message":
+		sp, ok := s.speak(w, r)
+		if !ok {
+			return
+		}
+		notes = internal.SongFromMessage(sp.message)
+		deterministic = sp.seeded
+	default:
+		s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", fmt.Sprintf("from must be random or message, got %q", from))
+		return
+	}
+
+	body, ok := s.renderSong(w, notes, opts)
+	if !ok {
+		return
+	}
+	s.writeCacheable(w, r, "audio/wav", body, deterministic)
+}
+
+// renderSong synthesizes notes as a WAV file. On failure it writes an error
+// response and returns false.
+func (s *Server) renderSong(w http.ResponseWriter, notes []internal.Note, opts internal.SongOptions) ([]byte, bool) {
+	var buf bytes.Buffer
+	if err := internal.WriteWAV(&buf, notes, opts); err != nil {
+		if errors.Is(err, internal.ErrInvalidSongOptions) {
+			s.writeErrorResponse(w, http.StatusBadRequest, "invalid_parameter", err.Error())
+			return nil, false
+		}
+		log.Printf("Failed to synthesize song: %v", err)
+		s.writeErrorResponse(w, http.StatusInternalServerError, "render_error", "Failed to synthesize song")
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// parseSongOptions parses the tempo, length and waveform parameters
+func parseSongOptions(r *http.Request) (internal.SongOptions, int, error) {
+	opts := internal.DefaultSongOptions()
+	length := internal.DefaultSongLength
+	query := r.URL.Query()
+
+	for key, target := range map[string]*int{"tempo": &opts.Tempo, "length": &length} {
+		if value := query.Get(key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return opts, 0, fmt.Errorf("invalid %s parameter: must be a number", key)
+			}
+			*target = n
+		}
+	}
+	if waveform := query.Get("waveform"); waveform != "" {
+		opts.Waveform = waveform
+	}
+	return opts, length, nil
+}
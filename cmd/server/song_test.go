@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerSong(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", target, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		return rr
+	}
+
+	for _, target := range []string{"/song.wav?seed=3&length=8&tempo=200", "/song.wav?from=message&amount=4&seed=3&waveform=sine"} {
+		rr := get(target)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, target, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "audio/wav" {
+			t.Errorf("Expected content type audio/wav, got %s", ct)
+		}
+		if body := rr.Body.Bytes(); len(body) < 44 || string(body[:4]) != "RIFF" {
+			t.Errorf("Expected a WAV file for %s", target)
+		}
+		if again := get(target); again.Body.String() != rr.Body.String() || rr.Header().Get("ETag") == "" {
+			t.Errorf("Expected a cacheable, stable song for %s", target)
+		}
+	}
+
+	if rr := get("/song.wav?length=4"); rr.Header().Get("ETag") != "" {
+		t.Error("Expected random songs not to be cached")
+	}
+}
+
+func TestServerSongInvalid(t *testing.T) {
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, query := range []string{"tempo=fast", "tempo=5", "length=0", "length=1000", "waveform=saw", "from=nowhere", "seed=x"} {
+		req, _ := http.NewRequest("GET", "/song.wav?"+query, nil)
+		rr := httptest.NewRecorder()
+		server.routes().ServeHTTP(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+}
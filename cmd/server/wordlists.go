@@ -0,0 +1,249 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+const (
+	// DefaultList is the name of the built-in Happy.TXT wordlist
+	DefaultList = "happy"
+	// MaxWordlistSize is the maximum accepted size of an uploaded wordlist
+	MaxWordlistSize = 4 << 20
+	// wordlistExt is the file extension used for persisted wordlists
+	wordlistExt = ".txt"
+)
+
+var (
+	// ErrUnknownList is returned when a wordlist name is not registered
+	ErrUnknownList = errors.New("unknown wordlist")
+	// ErrBuiltinList is returned when trying to modify the built-in wordlist
+	ErrBuiltinList = errors.New("the built-in wordlist cannot be modified")
+	// ErrInvalidListName is returned when a wordlist name is malformed
+	ErrInvalidListName = errors.New("list name must match [a-z0-9][a-z0-9_-]{0,63}")
+)
+
+var listNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// wordlist is a named wordlist hosted by the server
+type wordlist struct {
+	name      string
+	god       *internal.God
+	builtin   bool
+	updatedAt time.Time
+}
+
+// WordlistInfo describes a hosted wordlist in admin responses
+type WordlistInfo struct {
+	Name      string                 `json:"name"`
+	Builtin   bool                   `json:"builtin"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Stats     internal.WordlistStats `json:"stats"`
+}
+
+// wordlistRegistry holds the named wordlists and persists uploads to disk
+type wordlistRegistry struct {
+	mu      sync.RWMutex
+	lists   map[string]*wordlist
+	dataDir string
+}
+
+// newWordlistRegistry creates a registry containing the built-in wordlist
+// and any wordlists previously persisted to dataDir.
+func newWordlistRegistry(builtin *internal.God, dataDir string) (*wordlistRegistry, error) {
+	reg := &wordlistRegistry{
+		lists: map[string]*wordlist{
+			DefaultList: {name: DefaultList, god: builtin, builtin: true, updatedAt: time.Now()},
+		},
+		dataDir: dataDir,
+	}
+
+	if dataDir == "" {
+		return reg, nil
+	}
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := reg.load(); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// validateListName checks that name is usable as a wordlist name
+func validateListName(name string) error {
+	if !listNamePattern.MatchString(name) {
+		return ErrInvalidListName
+	}
+	return nil
+}
+
+// load reads every persisted wordlist from the data directory
+func (reg *wordlistRegistry) load() error {
+	paths, err := filepath.Glob(filepath.Join(reg.dataDir, "*"+wordlistExt))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), wordlistExt)
+		if validateListName(name) != nil || name == DefaultList {
+			log.Printf("Skipping wordlist file %s: invalid or reserved name", path)
+			continue
+		}
+
+		god, modTime, err := readWordlistFile(path)
+		if err != nil {
+			log.Printf("Skipping wordlist file %s: %v", path, err)
+			continue
+		}
+		reg.lists[name] = &wordlist{name: name, god: god, updatedAt: modTime}
+	}
+	return nil
+}
+
+// readWordlistFile parses a persisted wordlist file
+func readWordlistFile(path string) (*internal.God, time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	words, err := internal.ParseWords(f)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	god, err := internal.NewGodWithWords(words, internal.DefaultAmount)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return god, info.ModTime(), nil
+}
+
+// get returns the wordlist registered under name
+func (reg *wordlistRegistry) get(name string) (*wordlist, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	list, ok := reg.lists[name]
+	if !ok {
+		return nil, ErrUnknownList
+	}
+	return list, nil
+}
+
+// put creates or replaces a wordlist, persisting it when a data directory is
+// configured. It reports whether the wordlist was newly created.
+func (reg *wordlistRegistry) put(name string, words []string) (*wordlist, bool, error) {
+	if err := validateListName(name); err != nil {
+		return nil, false, err
+	}
+
+	god, err := internal.NewGodWithWords(words, internal.DefaultAmount)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	existing, ok := reg.lists[name]
+	if ok && existing.builtin {
+		return nil, false, ErrBuiltinList
+	}
+
+	if reg.dataDir != "" {
+		if err := writeFileAtomic(reg.path(name), []byte(strings.Join(god.Words(), "\n")+"\n")); err != nil {
+			return nil, false, fmt.Errorf("failed to persist wordlist: %w", err)
+		}
+	}
+
+	list := &wordlist{name: name, god: god, updatedAt: time.Now()}
+	reg.lists[name] = list
+	return list, !ok, nil
+}
+
+// delete removes a wordlist and its persisted file
+func (reg *wordlistRegistry) delete(name string) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	list, ok := reg.lists[name]
+	if !ok {
+		return ErrUnknownList
+	}
+	if list.builtin {
+		return ErrBuiltinList
+	}
+
+	if reg.dataDir != "" {
+		if err := os.Remove(reg.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove wordlist: %w", err)
+		}
+	}
+
+	delete(reg.lists, name)
+	return nil
+}
+
+// all returns every registered wordlist sorted by name
+func (reg *wordlistRegistry) all() []*wordlist {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	lists := make([]*wordlist, 0, len(reg.lists))
+	for _, list := range reg.lists {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].name < lists[j].name })
+	return lists
+}
+
+// path returns the file a wordlist is persisted to
+func (reg *wordlistRegistry) path(name string) string {
+	return filepath.Join(reg.dataDir, name+wordlistExt)
+}
+
+// info returns the admin description of a wordlist
+func (list *wordlist) info() WordlistInfo {
+	return WordlistInfo{
+		Name:      list.name,
+		Builtin:   list.builtin,
+		UpdatedAt: list.updatedAt,
+		Stats:     list.god.Stats(),
+	}
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testAdminToken = "test-token"
+
+func newAdminTestServer(t *testing.T, dataDir string) *Server {
+	t.Helper()
+
+	cfg := DefaultConfig()
+	cfg.DataDir = dataDir
+	cfg.AdminToken = testAdminToken
+	server, err := NewServerWithConfig(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+func adminRequest(t *testing.T, method, target, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+func TestAdminWordlistLifecycle(t *testing.T) {
+	dataDir := t.TempDir()
+	server := newAdminTestServer(t, dataDir)
+	r := server.routes()
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, adminRequest(t, "PUT", "/admin/lists/team-jargon", "synergy\nleverage\n\nparadigm\n"))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d on upload, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	var info WordlistInfo
+	if err := json.Unmarshal(rr.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if info.Name != "team-jargon" || info.Stats.Entries != 3 {
+		t.Errorf("Unexpected wordlist info: %+v", info)
+	}
+
+	if _, err := os.Stat(filepath.Join(dataDir, "team-jargon.txt")); err != nil {
+		t.Errorf("Expected wordlist to be persisted: %v", err)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, adminRequest(t, "PUT", "/admin/lists/team-jargon", "synergy\n"))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d on replace, got %d", http.StatusOK, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, adminRequest(t, "GET", "/admin/lists", ""))
+	var infos []WordlistInfo
+	if err := json.Unmarshal(rr.Body.Bytes(), &infos); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if len(infos) != 2 || infos[0].Name != DefaultList || infos[1].Name != "team-jargon" {
+		t.Errorf("Unexpected wordlists: %+v", infos)
+	}
+
+	req, _ := http.NewRequest("GET", "/?list=team-jargon&amount=3", nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Body.String() != "synergy synergy synergy" {
+		t.Errorf("Expected message from uploaded list, got %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, adminRequest(t, "DELETE", "/admin/lists/team-jargon", ""))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d on delete, got %d", http.StatusNoContent, rr.Code)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "team-jargon.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected persisted wordlist to be removed, got %v", err)
+	}
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, adminRequest(t, "GET", "/admin/lists/team-jargon", ""))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for deleted list, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestAdminWordlistReload(t *testing.T) {
+	dataDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dataDir, "saved.txt"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "Bad Name.txt"), []byte("one\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write wordlist: %v", err)
+	}
+
+	server := newAdminTestServer(t, dataDir)
+	list, err := server.lists.get("saved")
+	if err != nil {
+		t.Fatalf("Expected persisted wordlist to be reloaded: %v", err)
+	}
+	if list.god.GetWordsCount() != 2 {
+		t.Errorf("Expected 2 words, got %d", list.god.GetWordsCount())
+	}
+	if len(server.lists.all()) != 2 {
+		t.Errorf("Expected invalid file names to be skipped, got %d lists", len(server.lists.all()))
+	}
+}
+
+func TestAdminWordlistErrors(t *testing.T) {
+	server := newAdminTestServer(t, "")
+	r := server.routes()
+
+	testCases := []struct {
+		method, target, body string
+		expected             int
+	}{
+		{"PUT", "/admin/lists/happy", "x", http.StatusForbidden},
+		{"DELETE", "/admin/lists/happy", "", http.StatusForbidden},
+		{"PUT", "/admin/lists/Bad_Name", "x", http.StatusBadRequest},
+		{"PUT", "/admin/lists/empty", "\n\n", http.StatusBadRequest},
+		{"DELETE", "/admin/lists/missing", "", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, adminRequest(t, tc.method, tc.target, tc.body))
+		if rr.Code != tc.expected {
+			t.Errorf("Expected status %d for %s %s, got %d", tc.expected, tc.method, tc.target, rr.Code)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/json?list=missing", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown list, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	server := newAdminTestServer(t, "")
+	r := server.routes()
+
+	req, _ := http.NewRequest("GET", "/admin/lists", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without token, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer wrong")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d with wrong token, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	// Admin routes are not registered without a token
+	plain, err := NewServer()
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	rr = httptest.NewRecorder()
+	plain.routes().ServeHTTP(rr, adminRequest(t, "GET", "/admin/lists", ""))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when admin is disabled, got %d", http.StatusNotFound, rr.Code)
+	}
+}
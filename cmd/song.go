@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// writeSong sings message into a WAV file at path
+func writeSong(path, message string, opts internal.SongOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := internal.WriteWAV(f, internal.SongFromMessage(message), opts); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omid3699/god_says/cmd/server"
+	"github.com/omid3699/god_says/internal"
+)
+
+// speakCommand prints a message, optionally rendering it as ASCII art, an
+// image, a song or a QR code
+var speakCommand = &command{
+	name:    "speak",
+	summary: "Print a message from God (the default command)",
+	setup:   setupSpeak,
+}
+
+// setupSpeak defines the speak flags
+func setupSpeak(fs *flag.FlagSet, g *globals) func(args []string) error {
+	g.flags(fs, "amount", "tz", "namespace", "data-dir", "base-url", "no-wrap", "color", "save", "history-file")
+	var (
+		today = fs.Bool("today", false, "Print the message of the day instead of a random message")
+		list  = fs.String("list", server.DefaultList, "Wordlist to speak from: happy, NAME.txt in -data-dir, or a list hosted by -remote")
+
+		outputFormat = fs.String("output", "text", "Output format: "+strings.Join(outputFormats, ", ")+"; all but text include the words, list, amount and seed")
+
+		fortune       = fs.Bool("fortune", false, "Behave like fortune(6), wrapping the message and honoring -s, -l and -n")
+		fortuneShort  = fs.Bool("s", false, "Short fortunes only (with -fortune)")
+		fortuneLong   = fs.Bool("l", false, "Long fortunes only (with -fortune)")
+		fortuneLength = fs.Int("n", internal.DefaultFortuneLength, "Longest short fortune in characters (with -fortune)")
+
+		imagePath    = fs.String("image", "", "Also render the message to an image file (.png or .svg)")
+		imageWidth   = fs.Int("image-width", internal.DefaultImageWidth, "Image width in pixels")
+		imageScale   = fs.Int("image-scale", internal.DefaultImageOptions().Scale, "Image font scale")
+		imageTheme   = fs.String("image-theme", "temple", "Image theme: "+strings.Join(internal.ThemeNames(), ", "))
+		imagePalette = fs.String("image-palette", "mono", "Image word colors: "+strings.Join(internal.Palettes, ", "))
+
+		ascii          = fs.Bool("ascii", false, "Print the message in a cowsay style speech bubble")
+		asciiCharacter = fs.String("ascii-character", "temple", "Character saying the message: "+strings.Join(internal.CharacterNames(), ", "))
+		asciiWidth     = fs.Int("ascii-width", internal.DefaultASCIIWidth, "Speech bubble wrap width")
+		asciiBorder    = fs.String("ascii-border", "unicode", "Speech bubble border: "+strings.Join(internal.Borders, ", "))
+		asciiBanner    = fs.Bool("ascii-banner", false, fmt.Sprintf("Draw the message in large letters (up to %d characters)", internal.MaxBannerLength))
+
+		songPath     = fs.String("song", "", "Also sing the message into a WAV file")
+		songTempo    = fs.Int("song-tempo", internal.DefaultTempo, fmt.Sprintf("Song tempo in beats per minute (%d - %d)", internal.MinTempo, internal.MaxTempo))
+		songWaveform = fs.String("song-waveform", internal.DefaultSongOptions().Waveform, "Song waveform: "+strings.Join(internal.Waveforms, ", "))
+
+		qrPath   = fs.String("qr", "", "Also encode the message as a QR code (.png, .svg, or - to draw it in the terminal)")
+		qrLevel  = fs.String("qr-level", "M", "QR code error correction level: L, M, Q or H")
+		qrScale  = fs.Int("qr-scale", internal.DefaultQRScale, "QR code module size in pixels")
+		qrInvert = fs.Bool("qr-invert", false, "Draw terminal QR codes for light backgrounds")
+		qrLink   = fs.Bool("qr-link", false, "Encode a permalink to the message on -base-url (or -remote) instead of the message")
+
+		every     = fs.Duration("every", 0, "Keep printing a new message at this interval, e.g. 30s, until interrupted")
+		count     = fs.Int("count", 0, "Stop after this many messages (with -every, 0 means no limit)")
+		clear     = fs.Bool("clear", false, "Clear the screen before each message (with -every)")
+		overwrite = fs.Bool("overwrite", false, "Overwrite a single line with each message, e.g. for status bars (with -every)")
+
+		remote   remoteOptions
+		fallback = fs.Bool("remote-fallback", true, "Generate locally when the -remote server is unreachable")
+	)
+	var seed *int64
+	fs.Func("seed", "Seed making the message reproducible", func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be a 64-bit integer")
+		}
+		seed = &n
+		return nil
+	})
+	fs.StringVar(&remote.URL, "remote", os.Getenv("GODSAYS_REMOTE"), "Fetch messages from the server at this URL, e.g. https://host:3333 (defaults to $GODSAYS_REMOTE)")
+	fs.StringVar(&remote.Token, "remote-token", os.Getenv("GODSAYS_TOKEN"), "Bearer token sent to the -remote server (defaults to $GODSAYS_TOKEN)")
+	fs.StringVar(&remote.CAFile, "remote-ca", "", "PEM file of certificate authorities trusted for the -remote server")
+	fs.StringVar(&remote.CertFile, "remote-cert", "", "PEM client certificate for the -remote server")
+	fs.StringVar(&remote.KeyFile, "remote-key", "", "PEM private key of -remote-cert")
+	fs.BoolVar(&remote.Insecure, "remote-insecure", false, "Skip verifying the -remote server's TLS certificate")
+	fs.DurationVar(&remote.Timeout, "remote-timeout", DefaultRemoteTimeout, "Timeout of requests to the -remote server")
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		if err := g.validateAmount(); err != nil {
+			return err
+		}
+		loc, err := g.location()
+		if err != nil {
+			return err
+		}
+		base := g.baseURL
+		if base == "" {
+			base = remote.URL
+		}
+		if *qrLink && base == "" {
+			return fmt.Errorf("-qr-link requires -base-url or -remote")
+		}
+		if *fortune && remote.URL != "" {
+			return fmt.Errorf("-fortune cannot be combined with -remote")
+		}
+		if *every < 0 || *count < 0 {
+			return fmt.Errorf("-every and -count must not be negative")
+		}
+		if (*clear || *overwrite || *count > 0) && *every == 0 {
+			return fmt.Errorf("-clear, -overwrite and -count require -every")
+		}
+		if *clear && *overwrite {
+			return fmt.Errorf("-clear cannot be combined with -overwrite")
+		}
+		if *overwrite && *ascii {
+			return fmt.Errorf("-overwrite cannot be combined with -ascii")
+		}
+		output, err := newMessageWriter(os.Stdout, *outputFormat)
+		if err != nil {
+			return err
+		}
+		terminal, err := g.terminalOptions(os.Stdout)
+		if err != nil {
+			return err
+		}
+		if *outputFormat != "text" && (*ascii || *fortune || *overwrite) {
+			return fmt.Errorf("-output %s cannot be combined with -ascii, -fortune or -overwrite", *outputFormat)
+		}
+		history, err := g.history()
+		if err != nil {
+			return err
+		}
+		kind := "speak"
+		if *today {
+			kind = "today"
+		} else if *fortune {
+			kind = "fortune"
+		}
+
+		// seeds draws the seeds of the following messages of a seeded watch.
+		// Consecutive seeds would start with correlated words.
+		var seeds *rand.Rand
+		if seed != nil {
+			seeds = rand.New(rand.NewSource(*seed))
+		}
+
+		// the client and the local wordlist are set up once for a whole watch,
+		// the wordlist only when a message is generated locally
+		var (
+			client *remoteClient
+			god    *internal.God
+		)
+		if remote.URL != "" {
+			if client, err = newRemoteClient(remote); err != nil {
+				return err
+			}
+		}
+
+		// show prints the i-th message of a watch, or the only one
+		show := func(i int) error {
+			messageSeed := seed
+			if seed != nil && i > 0 {
+				n := seeds.Int63()
+				messageSeed = &n
+			} else if *qrLink && seed == nil {
+				// a seeded message can be reproduced from its permalink
+				n := rand.Int63()
+				messageSeed = &n
+			}
+
+			var response server.GodResponse
+			var link string
+			var err error
+			if client != nil {
+				response, link, err = speakRemote(client, g.amount, messageSeed, *list, *today, base)
+				if errors.Is(err, errUnreachable) && *fallback {
+					fmt.Fprintf(os.Stderr, "Warning: %v, generating locally\n", err)
+				} else if err != nil {
+					return err
+				}
+			}
+
+			if response.GodSays == "" {
+				if god == nil {
+					if god, err = openList(g.dataDir, *list, g.amount); err != nil {
+						return fmt.Errorf("failed to initialize God Says %w", err)
+					}
+				}
+
+				response = server.GodResponse{List: *list, Amount: g.amount}
+				switch {
+				case *today:
+					date := time.Now().In(loc)
+					daySeed := internal.DaySeed(date, g.namespace)
+					response.Words, err = god.SpeakSeededWords(g.amount, daySeed)
+					response.Seed = &daySeed
+					link = dayPermalink(base, date, g.amount, *list)
+				case *fortune:
+					opts := internal.FortuneOptions{Short: *fortuneShort, Long: *fortuneLong, Length: *fortuneLength}
+					response.GodSays, err = god.Fortune(opts)
+				case messageSeed != nil:
+					response.Words, err = god.SpeakSeededWords(g.amount, *messageSeed)
+					response.Seed = messageSeed
+					link = seedPermalink(base, *messageSeed, g.amount, *list)
+				default:
+					response.Words, err = god.SpeakWords(g.amount)
+				}
+				if err != nil {
+					return err
+				}
+				if response.GodSays == "" {
+					response.GodSays = strings.Join(response.Words, " ")
+				}
+			}
+			message := response.GodSays
+			record(history, internal.HistoryEntry{Source: "cli", Kind: kind, GodSays: message, Words: response.Words, List: response.List, Amount: response.Amount, Seed: response.Seed})
+
+			if *clear {
+				fmt.Print(clearScreen)
+			}
+			if *outputFormat != "text" {
+				if err := output.write(response); err != nil {
+					return err
+				}
+			} else if *ascii {
+				opts := internal.ASCIIOptions{Width: *asciiWidth, Character: *asciiCharacter, Border: *asciiBorder, Banner: *asciiBanner}
+				art, err := internal.RenderASCII(message, opts)
+				if err != nil {
+					return err
+				}
+				fmt.Print(art)
+			} else if *overwrite {
+				fmt.Print(overwriteLine(message, terminalWidth(int(os.Stdout.Fd()))))
+			} else if *fortune {
+				fmt.Println(strings.Join(internal.WrapText(message, internal.FortuneWidth), "\n"))
+			} else {
+				words := response.Words
+				if len(words) == 0 {
+					words = strings.Fields(message)
+				}
+				fmt.Println(internal.FormatTerminal(words, terminal))
+			}
+
+			if *imagePath != "" {
+				opts := internal.ImageOptions{Width: *imageWidth, Scale: *imageScale, Theme: *imageTheme, Palette: *imagePalette}
+				if err := writeImage(*imagePath, message, opts); err != nil {
+					return fmt.Errorf("failed to write image: %w", err)
+				}
+			}
+
+			if *songPath != "" {
+				opts := internal.SongOptions{Tempo: *songTempo, Waveform: *songWaveform}
+				if err := writeSong(*songPath, message, opts); err != nil {
+					return fmt.Errorf("failed to write song: %w", err)
+				}
+			}
+
+			if *qrPath != "" {
+				data := message
+				if *qrLink {
+					data = link
+				}
+				if err := writeQR(*qrPath, data, *qrLevel, *qrScale, *qrInvert); err != nil {
+					return fmt.Errorf("failed to write QR code: %w", err)
+				}
+			}
+			return nil
+		}
+
+		if *every == 0 {
+			return show(0)
+		}
+		err = watch(*every, *count, show)
+		if *overwrite {
+			// leave the shell prompt below the last message
+			fmt.Println()
+		}
+		return err
+	}
+}
+
+// speakRemote fetches the message and its permalink from a server
+func speakRemote(client *remoteClient, amount int, seed *int64, list string, today bool, base string) (server.GodResponse, string, error) {
+	if today {
+		day, err := client.today(amount, list)
+		if err != nil {
+			return server.GodResponse{}, "", err
+		}
+		date, err := time.Parse(internal.DateSaltLayout, day.Date)
+		if err != nil {
+			return server.GodResponse{}, "", fmt.Errorf("invalid date in server response: %w", err)
+		}
+		response := server.GodResponse{GodSays: day.GodSays, Words: day.Words, List: day.List, Amount: day.Amount, Seed: &day.Seed}
+		return response, dayPermalink(base, date, day.Amount, day.List), nil
+	}
+
+	response, err := client.speak(amount, seed, list)
+	if err != nil {
+		return server.GodResponse{}, "", err
+	}
+	link := ""
+	if response.Seed != nil {
+		link = seedPermalink(base, *response.Seed, response.Amount, response.List)
+	}
+	return response, link, nil
+}
@@ -0,0 +1,28 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import "errors"
+
+// terminalState is unused where raw mode is not supported
+type terminalState struct{}
+
+// isTerminal reports false, so input is read line by line
+func isTerminal(fd int) bool {
+	return false
+}
+
+// makeRaw is not supported on this platform
+func makeRaw(fd int) (*terminalState, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+// restoreTerminal is not supported on this platform
+func restoreTerminal(fd int, state *terminalState) error {
+	return nil
+}
+
+// terminalWidth reports 0, meaning the width is unknown
+func terminalWidth(fd int) int {
+	return 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/omid3699/god_says/internal"
+)
+
+// versionCommand prints build information
+var versionCommand = &command{
+	name:    "version",
+	summary: "Print the version of godsays",
+	setup:   setupVersion,
+}
+
+// setupVersion defines the version flags
+func setupVersion(fs *flag.FlagSet, g *globals) func(args []string) error {
+	asJSON := fs.Bool("json", false, "Print the build information as JSON")
+
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		info := internal.GetBuildInfo()
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(info)
+		}
+
+		fmt.Printf("godsays %s\n", info.Version)
+		if info.Commit != "" {
+			modified := ""
+			if info.Modified {
+				modified = " (modified)"
+			}
+			fmt.Printf("commit %s%s\n", info.Commit, modified)
+		}
+		if info.BuildTime != "" {
+			fmt.Printf("built %s\n", info.BuildTime)
+		}
+		fmt.Printf("%s %s/%s\n", info.GoVersion, info.OS, info.Arch)
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"log"
 	"syscall/js"
+	"time"
 
 	"github.com/omid3699/god_says/internal"
 )
@@ -31,9 +32,33 @@ func speak(this js.Value, args []js.Value) any {
 	return map[string]any{"ok": true, "message": g.Speak()}
 }
 
+func ask(this js.Value, args []js.Value) any {
+	if g == nil {
+		return map[string]any{"ok": false, "error": "god not initialized"}
+	}
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return map[string]any{"ok": false, "error": "question must be a string"}
+	}
+
+	var (
+		answer string
+		err    error
+	)
+	if len(args) > 1 && args[1].Truthy() {
+		answer, err = g.AskOn(args[0].String(), time.Now())
+	} else {
+		answer, err = g.Ask(args[0].String())
+	}
+	if err != nil {
+		return map[string]any{"ok": false, "error": err.Error()}
+	}
+	return map[string]any{"ok": true, "message": answer}
+}
+
 func main() {
 	js.Global().Set("initGod", js.FuncOf(initGod))
 	js.Global().Set("speak", js.FuncOf(speak))
+	js.Global().Set("ask", js.FuncOf(ask))
 
 	log.Println("GodSays WASM ready")
 	select {} // keep running
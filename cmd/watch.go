@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// clearScreen clears the terminal and moves the cursor home
+const clearScreen = "\x1b[H\x1b[2J"
+
+// watch calls show with increasing indexes every interval until count
+// messages were shown, or forever when count is 0. SIGINT and SIGTERM stop
+// it cleanly between messages.
+func watch(every time.Duration, count int, show func(i int) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for i := 0; count == 0 || i < count; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := show(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overwriteLine returns message as a single line replacing the current one,
+// cut to width columns so it does not wrap when width is known
+func overwriteLine(message string, width int) string {
+	message = strings.Join(strings.Fields(message), " ")
+	if width > 1 && utf8.RuneCountInString(message) > width-1 {
+		runes := []rune(message)
+		message = string(runes[:width-2]) + "…"
+	}
+	return "\r\x1b[K" + message
+}
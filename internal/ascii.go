@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultASCIIWidth is the default wrap width of speech bubbles
+	DefaultASCIIWidth = 40
+	// MinASCIIWidth and MaxASCIIWidth bound the speech bubble wrap width
+	MinASCIIWidth = 8
+	MaxASCIIWidth = 200
+	// MaxBannerLength is the longest message rendered as a banner
+	MaxBannerLength = 64
+)
+
+// ErrInvalidASCIIOptions is returned when ASCII art options are out of range
+var ErrInvalidASCIIOptions = errors.New("invalid ASCII options")
+
+// Characters are the figures that can say a message, cowsay style. The
+// bubble tail leads into the top of every figure.
+var Characters = map[string]string{
+	"none": "",
+	"temple": `    \
+     \     _/\_
+      \   /____\
+          | oo |
+          | __ |
+         /|____|\
+        / |    | \
+          |____|
+          /_||_\`,
+	"cow": `        \   ^__^
+         \  (oo)\_______
+            (__)\       )\/\
+                ||----w |
+                ||     ||`,
+	"angel": `    \    .-"-.
+     \  ( o o )
+      \  \ - /
+     \\__)   (__//
+         |     |
+        /_______\`,
+}
+
+// Borders are the speech bubble border styles
+var Borders = []string{"unicode", "plain"}
+
+// ASCIIOptions configures rendering of a message as ASCII art
+type ASCIIOptions struct {
+	// Width is the wrap width of the text inside the bubble
+	Width     int
+	Character string
+	Border    string
+	// Banner renders the message in large letters built from the 8x8 font
+	Banner bool
+}
+
+// DefaultASCIIOptions returns options for a TempleOS figure in a unicode bubble
+func DefaultASCIIOptions() ASCIIOptions {
+	return ASCIIOptions{Width: DefaultASCIIWidth, Character: "temple", Border: "unicode"}
+}
+
+// CharacterNames returns the sorted names of the available characters
+func CharacterNames() []string {
+	names := make([]string, 0, len(Characters))
+	for name := range Characters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate checks the ASCII art options
+func (opts ASCIIOptions) validate() error {
+	if opts.Width < MinASCIIWidth || opts.Width > MaxASCIIWidth {
+		return fmt.Errorf("%w: width must be between %d and %d", ErrInvalidASCIIOptions, MinASCIIWidth, MaxASCIIWidth)
+	}
+	if _, ok := Characters[opts.Character]; !ok {
+		return fmt.Errorf("%w: unknown character %q", ErrInvalidASCIIOptions, opts.Character)
+	}
+	if !slices.Contains(Borders, opts.Border) {
+		return fmt.Errorf("%w: unknown border %q", ErrInvalidASCIIOptions, opts.Border)
+	}
+	return nil
+}
+
+// RenderASCII renders message in a speech bubble said by a character
+func RenderASCII(message string, opts ASCIIOptions) (string, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	if opts.Banner {
+		message = strings.Join(strings.Fields(message), " ")
+		if utf8.RuneCountInString(message) > MaxBannerLength {
+			return "", fmt.Errorf("%w: banner text longer than %d characters", ErrInvalidASCIIOptions, MaxBannerLength)
+		}
+		lines = banner(message, opts)
+	} else {
+		lines = WrapText(message, opts.Width)
+	}
+
+	var b strings.Builder
+	writeBubble(&b, lines, opts.Border)
+	if art := Characters[opts.Character]; art != "" {
+		b.WriteString(art)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// WrapText wraps message into lines of at most width characters, breaking
+// words longer than a line
+func WrapText(message string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(message) {
+		runes := []rune(word)
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		for _, r := range runes {
+			if len(line) == width {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			line = append(line, r)
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// banner draws message in 8x8 font letters, wrapping whole letters at the
+// bubble width. Unicode borders pack two pixel rows into half blocks.
+func banner(message string, opts ASCIIOptions) []string {
+	var lines []string
+	for _, text := range WrapText(message, max(opts.Width/glyphSize, 1)) {
+		var rows [glyphSize]strings.Builder
+		for _, r := range text {
+			g := glyph(r)
+			for y := range rows {
+				for x := 0; x < glyphSize; x++ {
+					if g[y]&(1<<x) != 0 {
+						rows[y].WriteByte('#')
+					} else {
+						rows[y].WriteByte(' ')
+					}
+				}
+			}
+		}
+
+		if opts.Border == "plain" {
+			for y := range rows {
+				lines = append(lines, strings.TrimRight(rows[y].String(), " "))
+			}
+			continue
+		}
+		for y := 0; y < glyphSize; y += 2 {
+			top, bottom := rows[y].String(), rows[y+1].String()
+			var line strings.Builder
+			for x := range top {
+				switch {
+				case top[x] == '#' && bottom[x] == '#':
+					line.WriteString("█")
+				case top[x] == '#':
+					line.WriteString("▀")
+				case bottom[x] == '#':
+					line.WriteString("▄")
+				default:
+					line.WriteString(" ")
+				}
+			}
+			lines = append(lines, strings.TrimRight(line.String(), " "))
+		}
+	}
+
+	// drop blank rows below the letters, such as the empty last font row
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// writeBubble draws lines inside a speech bubble. Plain borders follow
+// cowsay: one line sits in <angle brackets>, more lines get slanted corners.
+func writeBubble(b *strings.Builder, lines []string, border string) {
+	width := 0
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+	pad := func(line string) string {
+		return line + strings.Repeat(" ", width-utf8.RuneCountInString(line))
+	}
+
+	if border == "unicode" {
+		fmt.Fprintf(b, "╭%s╮\n", strings.Repeat("─", width+2))
+		for _, line := range lines {
+			fmt.Fprintf(b, "│ %s │\n", pad(line))
+		}
+		fmt.Fprintf(b, "╰%s╯\n", strings.Repeat("─", width+2))
+		return
+	}
+
+	fmt.Fprintf(b, " %s\n", strings.Repeat("_", width+2))
+	for i, line := range lines {
+		left, right := "|", "|"
+		switch {
+		case len(lines) == 1:
+			left, right = "<", ">"
+		case i == 0:
+			left, right = "/", "\\"
+		case i == len(lines)-1:
+			left, right = "\\", "/"
+		}
+		fmt.Fprintf(b, "%s %s %s\n", left, pad(line), right)
+	}
+	fmt.Fprintf(b, " %s\n", strings.Repeat("-", width+2))
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	lines := WrapText("God says hello supercalifragilistic", 10)
+	expected := []string{"God says", "hello", "supercalif", "ragilistic"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, lines)
+	}
+
+	if lines := WrapText("", 10); len(lines) != 1 || lines[0] != "" {
+		t.Errorf("Expected a single empty line, got %q", lines)
+	}
+}
+
+func TestRenderASCIIPlain(t *testing.T) {
+	opts := ASCIIOptions{Width: 10, Character: "none", Border: "plain"}
+
+	art, err := RenderASCII("God says", opts)
+	if err != nil {
+		t.Fatalf("Failed to render ASCII: %v", err)
+	}
+	expected := " __________\n< God says >\n ----------\n"
+	if art != expected {
+		t.Errorf("Expected %q, got %q", expected, art)
+	}
+
+	art, err = RenderASCII("God says hello world", opts)
+	if err != nil {
+		t.Fatalf("Failed to render ASCII: %v", err)
+	}
+	expected = " __________\n/ God says \\\n| hello    |\n\\ world    /\n ----------\n"
+	if art != expected {
+		t.Errorf("Expected %q, got %q", expected, art)
+	}
+}
+
+func TestRenderASCIIUnicode(t *testing.T) {
+	opts := DefaultASCIIOptions()
+	opts.Character = "cow"
+
+	art, err := RenderASCII("God says", opts)
+	if err != nil {
+		t.Fatalf("Failed to render ASCII: %v", err)
+	}
+	lines := strings.Split(art, "\n")
+	if lines[0] != "╭──────────╮" || lines[1] != "│ God says │" || lines[2] != "╰──────────╯" {
+		t.Errorf("Unexpected bubble:\n%s", art)
+	}
+	if !strings.Contains(art, "(oo)") {
+		t.Errorf("Expected the cow to speak:\n%s", art)
+	}
+}
+
+func TestRenderASCIICharacters(t *testing.T) {
+	for _, name := range CharacterNames() {
+		opts := DefaultASCIIOptions()
+		opts.Character = name
+		art, err := RenderASCII("God", opts)
+		if err != nil {
+			t.Errorf("Failed to render character %s: %v", name, err)
+			continue
+		}
+		if !strings.HasSuffix(art, "\n") {
+			t.Errorf("Expected character %s to end with a newline", name)
+		}
+	}
+}
+
+func TestRenderASCIIBanner(t *testing.T) {
+	opts := ASCIIOptions{Width: 16, Character: "none", Border: "plain", Banner: true}
+
+	art, err := RenderASCII("Hi God", opts)
+	if err != nil {
+		t.Fatalf("Failed to render banner: %v", err)
+	}
+	// Two letters per line: "Hi" and "God" wrapped into "Go" and "d"
+	lines := strings.Split(strings.TrimSuffix(art, "\n"), "\n")
+	if strings.Contains(art, "Hi") || len(lines) < 2+16 {
+		t.Errorf("Expected large letters over several rows:\n%s", art)
+	}
+	if !strings.Contains(lines[1], "##") {
+		t.Errorf("Expected the first row to be drawn with '#':\n%s", art)
+	}
+
+	opts.Border = "unicode"
+	art, err = RenderASCII("Hi", opts)
+	if err != nil {
+		t.Fatalf("Failed to render banner: %v", err)
+	}
+	if !strings.Contains(art, "█") || strings.Count(art, "\n") != 2+4 {
+		t.Errorf("Expected a half block banner four rows high:\n%s", art)
+	}
+
+	if _, err := RenderASCII(strings.Repeat("x", MaxBannerLength+1), opts); !errors.Is(err, ErrInvalidASCIIOptions) {
+		t.Errorf("Expected ErrInvalidASCIIOptions for long banners, got %v", err)
+	}
+}
+
+func TestRenderASCIIInvalidOptions(t *testing.T) {
+	testCases := []ASCIIOptions{
+		{Width: 2, Character: "temple", Border: "plain"},
+		{Width: 40, Character: "dragon", Border: "plain"},
+		{Width: 40, Character: "temple", Border: "double"},
+	}
+	for _, opts := range testCases {
+		if _, err := RenderASCII("God", opts); !errors.Is(err, ErrInvalidASCIIOptions) {
+			t.Errorf("Expected ErrInvalidASCIIOptions for %+v, got %v", opts, err)
+		}
+	}
+}
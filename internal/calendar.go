@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// icalDateLayout is the layout of iCalendar DATE values
+	icalDateLayout = "20060102"
+	// icalLineLimit is the maximum line length in octets before folding
+	icalLineLimit = 75
+)
+
+// ICalendar renders the feed as an iCalendar document with one all-day event
+// per entry. Every property is derived from the entry date and link, so the
+// document is byte-for-byte stable across requests.
+func (f Feed) ICalendar() []byte {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//godsays//God Says//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(f.Title))
+
+	for _, entry := range f.Entries {
+		sum := sha256.Sum256([]byte(entry.Link))
+		date := entry.Date.Format(icalDateLayout)
+
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+date+"-"+hex.EncodeToString(sum[:8])+"@godsays")
+		writeICalLine(&b, "DTSTAMP:"+date+"T000000Z")
+		writeICalLine(&b, "DTSTART;VALUE=DATE:"+date)
+		writeICalLine(&b, "DTEND;VALUE=DATE:"+NextDay(entry.Date).Format(icalDateLayout))
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(entry.Message))
+		writeICalLine(&b, "DESCRIPTION:"+escapeICalText(entry.title()))
+		writeICalLine(&b, "URL:"+entry.Link)
+		writeICalLine(&b, "TRANSP:TRANSPARENT")
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeICalText escapes a TEXT property value
+func escapeICalText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(value)
+}
+
+// writeICalLine writes a content line, folding it at 75 octets without
+// splitting UTF-8 sequences, terminated by CRLF
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = icalLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDailyCalendar(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	opts := FeedOptions{
+		Title:     "God Says",
+		BaseURL:   "https://example.com",
+		Days:      3,
+		Amount:    8,
+		Namespace: DefaultNamespace,
+		Location:  time.UTC,
+		Now:       func() time.Time { return time.Date(2024, 12, 30, 18, 0, 0, 0, time.UTC) },
+	}
+	calendar, err := NewDailyCalendar(god, opts)
+	if err != nil {
+		t.Fatalf("Failed to build calendar: %v", err)
+	}
+
+	expectedDates := []string{"2024-12-30", "2024-12-31", "2025-01-01"}
+	for i, entry := range calendar.Entries {
+		if got := entry.Date.Format(DateSaltLayout); got != expectedDates[i] {
+			t.Errorf("Expected entry %d to be %s, got %s", i, expectedDates[i], got)
+		}
+	}
+
+	// Calendar and feed agree on the message of a given day
+	feed, _ := NewDailyFeed(god, opts)
+	if calendar.Entries[0].Message != feed.Entries[0].Message {
+		t.Error("Expected calendar and feed to share today's message")
+	}
+
+	first := calendar.ICalendar()
+	again, _ := NewDailyCalendar(god, opts)
+	if !bytes.Equal(first, again.ICalendar()) {
+		t.Error("Expected the calendar to be stable across builds")
+	}
+
+	ics := string(first)
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART;VALUE=DATE:20241231\r\n",
+		"DTEND;VALUE=DATE:20250101\r\n",
+		"DTSTAMP:20250101T000000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, line) {
+			t.Errorf("Expected calendar to contain %q", line)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 3 {
+		t.Errorf("Expected 3 events, got %d", strings.Count(ics, "BEGIN:VEVENT"))
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("Expected lines of at most %d octets, got %d: %q", icalLineLimit, len(line), line)
+		}
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	got := escapeICalText("a;b,c\\d\ne")
+	if got != `a\;b\,c\\d\ne` {
+		t.Errorf("Unexpected escaped text %q", got)
+	}
+}
+
+func TestWriteICalLineFolding(t *testing.T) {
+	var b strings.Builder
+	line := "SUMMARY:" + strings.Repeat("é", 100)
+	writeICalLine(&b, line)
+
+	folded := b.String()
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("Expected unfolding to restore the line, got %q", unfolded)
+	}
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > icalLineLimit {
+			t.Errorf("Expected folded lines of at most %d octets, got %d", icalLineLimit, len(part))
+		}
+		if !strings.HasPrefix(part, " ") && part != folded[:len(part)] {
+			t.Errorf("Expected continuation lines to start with a space: %q", part)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+// DefaultNamespace is the message of the day namespace used when none is
+// configured. Different namespaces get different messages for the same date.
+const DefaultNamespace = "godsays"
+
+// DaySeed derives the seed of the message of the day for the calendar day of
+// date, as observed in date's location.
+func DaySeed(date time.Time, namespace string) int64 {
+	sum := sha256.Sum256([]byte("day\x00" + namespace + "\x00" + date.Format(DateSaltLayout)))
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// Daily returns the message of the day for the calendar day of date. Convert
+// date with In first so everyone sharing a timezone sees the same message.
+func (g *God) Daily(date time.Time, namespace string) (string, error) {
+	return g.SpeakSeeded(g.GetAmount(), DaySeed(date, namespace))
+}
+
+// Today returns the message of the day for the current date in loc.
+func (g *God) Today(loc *time.Location, namespace string) (string, error) {
+	return g.Daily(time.Now().In(loc), namespace)
+}
+
+// ParseDay parses a YYYY-MM-DD date as midnight in loc.
+func ParseDay(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(DateSaltLayout, value, loc)
+}
+
+// NextDay returns midnight of the day following date in date's location.
+func NextDay(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, date.Location())
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGodDaily(t *testing.T) {
+	god, err := NewGod(12)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	morning := time.Date(2024, 12, 24, 6, 0, 0, 0, time.UTC)
+	night := time.Date(2024, 12, 24, 23, 59, 59, 0, time.UTC)
+	christmas := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	first, err := god.Daily(morning, DefaultNamespace)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := god.Daily(night, DefaultNamespace)
+	third, _ := god.Daily(christmas, DefaultNamespace)
+	otherTeam, _ := god.Daily(morning, "team")
+
+	if first == "" {
+		t.Fatal("Expected non-empty message")
+	}
+	if first != second {
+		t.Errorf("Expected the same message all day, got %q and %q", first, second)
+	}
+	if first == third {
+		t.Errorf("Expected a different message on the next day, got %q", first)
+	}
+	if first == otherTeam {
+		t.Errorf("Expected namespaces to get different messages, got %q", first)
+	}
+}
+
+func TestGodDailyTimezone(t *testing.T) {
+	god, err := NewGod(12)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 20:00 UTC on the 1st is already the 2nd in Tokyo
+	instant := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+
+	utcMessage, _ := god.Daily(instant, DefaultNamespace)
+	tokyoMessage, _ := god.Daily(instant.In(tokyo), DefaultNamespace)
+	secondUTC, _ := god.Daily(time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC), DefaultNamespace)
+
+	if utcMessage == tokyoMessage {
+		t.Errorf("Expected the date to depend on the timezone")
+	}
+	if tokyoMessage != secondUTC {
+		t.Errorf("Expected the same calendar day to give the same message across timezones")
+	}
+}
+
+func TestParseDay(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	day, err := ParseDay("2024-02-29", loc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if day.Location() != loc || day.Day() != 29 || day.Hour() != 0 {
+		t.Errorf("Unexpected parsed day: %v", day)
+	}
+
+	for _, value := range []string{"2023-02-29", "2024-1-01", "today", ""} {
+		if _, err := ParseDay(value, loc); err == nil {
+			t.Errorf("Expected error for %q, got none", value)
+		}
+	}
+
+	next := NextDay(time.Date(2024, 12, 31, 15, 0, 0, 0, loc))
+	if next.Year() != 2025 || next.Month() != time.January || next.Day() != 1 || next.Hour() != 0 {
+		t.Errorf("Unexpected next day: %v", next)
+	}
+}
@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FeedOptions configures a feed of daily messages
+type FeedOptions struct {
+	Title string
+	// BaseURL is prepended to the feed and entry links, e.g. https://example.com
+	BaseURL string
+	// Query is appended to entry links so they reproduce the same message
+	Query     url.Values
+	Days      int
+	Amount    int
+	Namespace string
+	Location  *time.Location
+	// Now is the time source deciding the most recent day of the feed; nil
+	// means time.Now
+	Now func() time.Time
+}
+
+// FeedEntry is the message of a single day
+type FeedEntry struct {
+	Date    time.Time
+	Message string
+	Link    string
+}
+
+// Feed is a list of daily messages, newest first
+type Feed struct {
+	Title   string
+	Link    string
+	Updated time.Time
+	Entries []FeedEntry
+}
+
+// NewDailyFeed builds a feed of the messages of the last opts.Days days,
+// newest first. Entries are derived from the date only, so their content and
+// links are stable across requests.
+func NewDailyFeed(g *God, opts FeedOptions) (Feed, error) {
+	return newDailyFeed(g, opts, -1)
+}
+
+// NewDailyCalendar builds a feed of the messages of the next opts.Days days,
+// starting today, oldest first.
+func NewDailyCalendar(g *God, opts FeedOptions) (Feed, error) {
+	return newDailyFeed(g, opts, 1)
+}
+
+// newDailyFeed builds a feed starting today and moving step days per entry
+func newDailyFeed(g *God, opts FeedOptions, step int) (Feed, error) {
+	if opts.Days < 1 {
+		return Feed{}, fmt.Errorf("feed must contain at least one day")
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	clock := opts.Now
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock().In(loc)
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	base := strings.TrimSuffix(opts.BaseURL, "/")
+
+	feed := Feed{Title: opts.Title, Link: base + "/today", Updated: today}
+	for i := 0; i < opts.Days; i++ {
+		day := time.Date(y, m, d+i*step, 0, 0, 0, 0, loc)
+		message, err := g.SpeakSeeded(opts.Amount, DaySeed(day, opts.Namespace))
+		if err != nil {
+			return Feed{}, err
+		}
+
+		link := base + "/day/" + day.Format(DateSaltLayout)
+		if len(opts.Query) > 0 {
+			link += "?" + opts.Query.Encode()
+		}
+		feed.Entries = append(feed.Entries, FeedEntry{Date: day, Message: message, Link: link})
+	}
+	return feed, nil
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the feed as an RSS 2.0 document
+func (f Feed) RSS() ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   "Daily messages from God",
+			LastBuildDate: f.Updated.Format(time.RFC1123Z),
+		},
+	}
+	for _, entry := range f.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entry.title(),
+			Link:        entry.Link,
+			Description: entry.Message,
+			PubDate:     entry.Date.Format(time.RFC1123Z),
+			GUID:        rssGUID{IsPermaLink: true, Value: entry.Link},
+		})
+	}
+	return marshalXML(doc)
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom renders the feed as an Atom 1.0 document
+func (f Feed) Atom() ([]byte, error) {
+	doc := atomDocument{
+		Title:   f.Title,
+		ID:      f.Link,
+		Updated: f.Updated.Format(time.RFC3339),
+		Links:   []atomLink{{Href: f.Link, Rel: "alternate"}},
+		Author:  atomAuthor{Name: "God"},
+	}
+	for _, entry := range f.Entries {
+		doc.Entries = append(doc.Entries, atomEntry{
+			Title:   entry.title(),
+			ID:      entry.Link,
+			Updated: entry.Date.Format(time.RFC3339),
+			Link:    atomLink{Href: entry.Link, Rel: "alternate"},
+			Content: atomContent{Type: "text", Value: entry.Message},
+		})
+	}
+	return marshalXML(doc)
+}
+
+// title returns the entry title shown in feed readers
+func (e FeedEntry) title() string {
+	return "God says for " + e.Date.Format(DateSaltLayout)
+}
+
+// marshalXML encodes doc as an indented XML document with a header
+func marshalXML(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func testFeed(t *testing.T, now time.Time) Feed {
+	t.Helper()
+
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	feed, err := NewDailyFeed(god, FeedOptions{
+		Title:     "God Says",
+		BaseURL:   "https://example.com/",
+		Query:     url.Values{"list": {"happy"}},
+		Days:      3,
+		Amount:    8,
+		Namespace: DefaultNamespace,
+		Location:  time.UTC,
+		Now:       func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("Failed to build feed: %v", err)
+	}
+	return feed
+}
+
+func TestNewDailyFeed(t *testing.T) {
+	now := time.Date(2024, 3, 1, 15, 4, 5, 0, time.UTC)
+	feed := testFeed(t, now)
+
+	if len(feed.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(feed.Entries))
+	}
+
+	expectedDates := []string{"2024-03-01", "2024-02-29", "2024-02-28"}
+	for i, entry := range feed.Entries {
+		if got := entry.Date.Format(DateSaltLayout); got != expectedDates[i] {
+			t.Errorf("Expected entry %d to be %s, got %s", i, expectedDates[i], got)
+		}
+		if entry.Link != "https://example.com/day/"+expectedDates[i]+"?list=happy" {
+			t.Errorf("Unexpected entry link %s", entry.Link)
+		}
+		if entry.Message == "" {
+			t.Errorf("Expected non-empty message for entry %d", i)
+		}
+	}
+
+	// Later the same day produces an identical feed
+	later := testFeed(t, now.Add(8*time.Hour))
+	first, _ := feed.RSS()
+	second, _ := later.RSS()
+	if !bytes.Equal(first, second) {
+		t.Error("Expected the feed to be stable within a day")
+	}
+
+	// The next day shifts entries by one with stable GUIDs
+	tomorrow := testFeed(t, now.Add(24*time.Hour))
+	if tomorrow.Entries[1].Link != feed.Entries[0].Link || tomorrow.Entries[1].Message != feed.Entries[0].Message {
+		t.Error("Expected yesterday's entry to keep its link and message")
+	}
+}
+
+func TestFeedRSS(t *testing.T) {
+	feed := testFeed(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	body, err := feed.RSS()
+	if err != nil {
+		t.Fatalf("Failed to render RSS: %v", err)
+	}
+
+	var doc rssDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Failed to parse RSS: %v", err)
+	}
+	if doc.Version != "2.0" || len(doc.Channel.Items) != 3 {
+		t.Fatalf("Unexpected RSS document: %+v", doc)
+	}
+
+	item := doc.Channel.Items[0]
+	if item.PubDate != "Fri, 01 Mar 2024 00:00:00 +0000" {
+		t.Errorf("Unexpected pubDate %s", item.PubDate)
+	}
+	if item.GUID.Value != item.Link || !item.GUID.IsPermaLink {
+		t.Errorf("Expected permalink GUID, got %+v", item.GUID)
+	}
+	if item.Description != feed.Entries[0].Message {
+		t.Errorf("Expected description to be the message")
+	}
+}
+
+func TestFeedAtom(t *testing.T) {
+	feed := testFeed(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	body, err := feed.Atom()
+	if err != nil {
+		t.Fatalf("Failed to render Atom: %v", err)
+	}
+
+	var doc atomDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Failed to parse Atom: %v", err)
+	}
+	if doc.XMLName.Space != "http://www.w3.org/2005/Atom" || len(doc.Entries) != 3 {
+		t.Fatalf("Unexpected Atom document: %+v", doc)
+	}
+	if doc.Updated != "2024-03-01T00:00:00Z" || doc.Entries[2].Updated != "2024-02-28T00:00:00Z" {
+		t.Errorf("Unexpected updated dates %s and %s", doc.Updated, doc.Entries[2].Updated)
+	}
+	if doc.Entries[0].ID != feed.Entries[0].Link {
+		t.Errorf("Expected entry id to be its link, got %s", doc.Entries[0].ID)
+	}
+}
+
+func TestNewDailyFeedInvalid(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	if _, err := NewDailyFeed(god, FeedOptions{Days: 0, Amount: 5, Now: time.Now}); err == nil {
+		t.Error("Expected error for zero days, got none")
+	}
+	if _, err := NewDailyFeed(god, FeedOptions{Days: 1, Amount: 0, Now: time.Now}); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := NewDailyCalendar(god, FeedOptions{}); err == nil {
+		t.Error("Expected error for zero value options, got none")
+	}
+}
+
+func TestNewDailyFeedDefaults(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	// without Now and Location the feed starts today in UTC
+	today := time.Now().UTC().Format(time.DateOnly)
+	for name, build := range map[string]func(*God, FeedOptions) (Feed, error){"feed": NewDailyFeed, "calendar": NewDailyCalendar} {
+		feed, err := build(god, FeedOptions{Days: 1, Amount: 5})
+		if err != nil {
+			t.Fatalf("Failed to build %s: %v", name, err)
+		}
+		if len(feed.Entries) != 1 || feed.Entries[0].Date.Format(time.DateOnly) != today {
+			t.Errorf("Expected a %s entry for %s, got %+v", name, today, feed.Entries)
+		}
+	}
+}
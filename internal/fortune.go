@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// DefaultFortuneLength is the length in characters separating short
+	// from long fortunes, like fortune(6)'s -n
+	DefaultFortuneLength = 160
+	// FortuneWidth is the line width messages are wrapped to in fortune
+	// databases
+	FortuneWidth = 72
+	// fortuneAttempts bounds how often a short fortune is retried
+	fortuneAttempts = 100
+	// strfileVersion is the version of the strfile(8) index format
+	strfileVersion = 2
+)
+
+var (
+	// ErrInvalidFortuneOptions is returned when fortune options conflict
+	ErrInvalidFortuneOptions = errors.New("invalid fortune options")
+	// ErrNoShortFortune is returned when no message fits a short fortune
+	ErrNoShortFortune = errors.New("no message is short enough")
+)
+
+// FortuneOptions selects fortunes by length like fortune(6)'s -s, -l and -n
+type FortuneOptions struct {
+	// Short only gives fortunes of at most Length characters
+	Short bool
+	// Long only gives fortunes longer than Length characters
+	Long   bool
+	Length int
+}
+
+// Fortune generates a message of the current amount, cut down to whole
+// words for short fortunes or extended with more words for long ones
+func (g *God) Fortune(opts FortuneOptions) (string, error) {
+	if opts.Short && opts.Long {
+		return "", fmt.Errorf("%w: short and long fortunes are exclusive", ErrInvalidFortuneOptions)
+	}
+	if opts.Length < 1 {
+		return "", fmt.Errorf("%w: length must be positive", ErrInvalidFortuneOptions)
+	}
+	if len(g.words) == 0 {
+		return "", ErrEmptyWordlist
+	}
+
+	amount := g.GetAmount()
+	switch {
+	case opts.Short:
+		for i := 0; i < fortuneAttempts; i++ {
+			var b strings.Builder
+			for _, word := range strings.Fields(g.generateMessage(amount)) {
+				length := b.Len() + len(word)
+				if b.Len() > 0 {
+					length++
+				}
+				if length > opts.Length {
+					break
+				}
+				if b.Len() > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(word)
+			}
+			if b.Len() > 0 {
+				return b.String(), nil
+			}
+		}
+		return "", fmt.Errorf("%w: %d characters", ErrNoShortFortune, opts.Length)
+	case opts.Long:
+		message := g.generateMessage(amount)
+		for words := amount; len(message) <= opts.Length && words < MaxAmount; words++ {
+			message += " " + g.generateMessage(1)
+		}
+		return message, nil
+	default:
+		return g.generateMessage(amount), nil
+	}
+}
+
+// WriteFortunes writes entries as a fortune(6) text database to text and its
+// strfile(8) index to dat. Messages are wrapped to FortuneWidth.
+func WriteFortunes(text, dat io.Writer, entries []string) error {
+	offsets := make([]uint32, 0, len(entries)+1)
+	var longest, shortest uint32
+	var offset uint32
+	for i, entry := range entries {
+		body := strings.Join(WrapText(entry, FortuneWidth), "\n") + "\n"
+		offsets = append(offsets, offset)
+		if _, err := io.WriteString(text, body+"%\n"); err != nil {
+			return err
+		}
+		offset += uint32(len(body) + len("%\n"))
+
+		length := uint32(len(body))
+		if i == 0 || length > longest {
+			longest = length
+		}
+		if i == 0 || length < shortest {
+			shortest = length
+		}
+	}
+	offsets = append(offsets, offset)
+
+	// str_version, str_numstr, str_longlen, str_shortlen, str_flags and
+	// the delimiter padded to four bytes, followed by the offsets
+	header := []uint32{strfileVersion, uint32(len(entries)), longest, shortest, 0}
+	if err := binary.Write(dat, binary.BigEndian, header); err != nil {
+		return err
+	}
+	if _, err := dat.Write([]byte{'%', 0, 0, 0}); err != nil {
+		return err
+	}
+	return binary.Write(dat, binary.BigEndian, offsets)
+}
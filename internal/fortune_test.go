@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFortune(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		short, err := god.Fortune(FortuneOptions{Short: true, Length: 40})
+		if err != nil {
+			t.Fatalf("Failed to generate short fortune: %v", err)
+		}
+		if len(short) == 0 || len(short) > 40 {
+			t.Errorf("Expected a short fortune of at most 40 characters, got %d: %q", len(short), short)
+		}
+
+		long, err := god.Fortune(FortuneOptions{Long: true, Length: 400})
+		if err != nil {
+			t.Fatalf("Failed to generate long fortune: %v", err)
+		}
+		if len(long) <= 400 {
+			t.Errorf("Expected a long fortune over 400 characters, got %d", len(long))
+		}
+	}
+
+	if _, err := god.Fortune(FortuneOptions{Length: DefaultFortuneLength}); err != nil {
+		t.Errorf("Failed to generate fortune: %v", err)
+	}
+}
+
+func TestFortuneInvalidOptions(t *testing.T) {
+	god, err := NewGodWithWords([]string{"supercalifragilistic"}, 4)
+	if err != nil {
+		t.Fatalf("Failed to create God: %v", err)
+	}
+
+	if _, err := god.Fortune(FortuneOptions{Short: true, Long: true, Length: 10}); !errors.Is(err, ErrInvalidFortuneOptions) {
+		t.Errorf("Expected ErrInvalidFortuneOptions for -s and -l, got %v", err)
+	}
+	if _, err := god.Fortune(FortuneOptions{Length: 0}); !errors.Is(err, ErrInvalidFortuneOptions) {
+		t.Errorf("Expected ErrInvalidFortuneOptions for zero length, got %v", err)
+	}
+	if _, err := god.Fortune(FortuneOptions{Short: true, Length: 5}); !errors.Is(err, ErrNoShortFortune) {
+		t.Errorf("Expected ErrNoShortFortune, got %v", err)
+	}
+}
+
+func TestWriteFortunes(t *testing.T) {
+	entries := []string{"God says hello", strings.Repeat("amen ", 20), "x"}
+
+	var text, dat bytes.Buffer
+	if err := WriteFortunes(&text, &dat, entries); err != nil {
+		t.Fatalf("Failed to write fortunes: %v", err)
+	}
+
+	var header struct {
+		Version, NumStr, LongLen, ShortLen, Flags uint32
+		Delim                                     [4]byte
+	}
+	if err := binary.Read(&dat, binary.BigEndian, &header); err != nil {
+		t.Fatalf("Failed to read header: %v", err)
+	}
+	if header.Version != 2 || header.NumStr != 3 || header.Delim[0] != '%' {
+		t.Errorf("Unexpected header %+v", header)
+	}
+	// lengths count the newlines of the wrapped text: "x\n" and two lines of amens
+	if header.ShortLen != 2 || header.LongLen != 100 {
+		t.Errorf("Expected lengths 2 and 100, got %d and %d", header.ShortLen, header.LongLen)
+	}
+
+	offsets := make([]uint32, header.NumStr+1)
+	if err := binary.Read(&dat, binary.BigEndian, offsets); err != nil {
+		t.Fatalf("Failed to read offsets: %v", err)
+	}
+	if dat.Len() != 0 {
+		t.Errorf("Expected %d trailing bytes to be offsets", dat.Len())
+	}
+	if int(offsets[header.NumStr]) != text.Len() {
+		t.Errorf("Expected the last offset to be the text size %d, got %d", text.Len(), offsets[header.NumStr])
+	}
+
+	// every offset starts a fortune ending at the next delimiter line
+	data := text.String()
+	for i, entry := range entries {
+		fortune := data[offsets[i]:offsets[i+1]]
+		if !strings.HasSuffix(fortune, "\n%\n") {
+			t.Errorf("Expected fortune %d to end with a delimiter, got %q", i, fortune)
+		}
+		if got := strings.Join(strings.Fields(strings.TrimSuffix(fortune, "%\n")), " "); got != strings.TrimSpace(entry) {
+			t.Errorf("Expected fortune %d to be %q, got %q", i, entry, got)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(fortune, "\n"), "\n") {
+			if len(line) > FortuneWidth {
+				t.Errorf("Expected lines of at most %d characters, got %d", FortuneWidth, len(line))
+			}
+		}
+	}
+}
@@ -5,21 +5,49 @@ import (
 	"bufio"
 	"embed"
 	"errors"
+	"io"
 	"math/rand"
+	randv2 "math/rand/v2"
 	"strings"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
 //go:embed Happy.TXT
 var happyFS embed.FS
 
 // God represents the god says functionality with thread-safe operations.
+// Random messages come from the runtime's per-thread generator, so
+// concurrent callers share no lock or counter.
 type God struct {
 	words  []string
-	amount int
-	rng    *rand.Rand
-	mu     sync.RWMutex // Protects amount field and RNG
+	amount atomic.Int64
+	// src picks the words of random messages
+	src source
+}
+
+// source draws random word indices and must be safe for concurrent use
+type source interface {
+	IntN(n int) int
+}
+
+// runtimeSource draws from the runtime's per-thread generator
+type runtimeSource struct{}
+
+// IntN returns a random int in [0, n)
+func (runtimeSource) IntN(n int) int {
+	return randv2.IntN(n)
+}
+
+// indexPool reuses the word index buffers messages are built from
+var indexPool = sync.Pool{
+	New: func() any { return new([]int) },
+}
+
+// seededPool reuses the RNGs of seeded messages, reseeding one produces the
+// same stream as a new RNG
+var seededPool = sync.Pool{
+	New: func() any { return rand.New(rand.NewSource(1)) },
 }
 
 const (
@@ -31,6 +59,9 @@ const (
 // ErrInvalidAmount is returned when an invalid amount is provided
 var ErrInvalidAmount = errors.New("amount must be between 1 and 1000")
 
+// ErrEmptyWordlist is returned when a wordlist contains no usable entries
+var ErrEmptyWordlist = errors.New("wordlist contains no words")
+
 // NewGod creates a new God instance with the specified amount of words to generate.
 func NewGod(amount int) (*God, error) {
 	if err := validateAmount(amount); err != nil {
@@ -42,15 +73,34 @@ func NewGod(amount int) (*God, error) {
 		return nil, err
 	}
 
-	// Create a new random source with current time as seed
-	source := rand.NewSource(time.Now().UnixNano())
-	rng := rand.New(source)
+	return newGod(words, amount), nil
+}
+
+// NewGodWithWords creates a new God instance speaking from a custom wordlist.
+func NewGodWithWords(words []string, amount int) (*God, error) {
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
 
-	return &God{
-		words:  words,
-		amount: amount,
-		rng:    rng,
-	}, nil
+	cleaned := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word != "" {
+			cleaned = append(cleaned, word)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, ErrEmptyWordlist
+	}
+
+	return newGod(cleaned, amount), nil
+}
+
+// newGod builds a God from an already cleaned wordlist
+func newGod(words []string, amount int) *God {
+	g := &God{words: words, src: runtimeSource{}}
+	g.amount.Store(int64(amount))
+	return g
 }
 
 // validateAmount checks if the provided amount is within valid range
@@ -63,13 +113,19 @@ func validateAmount(amount int) error {
 
 // readWords reads the Happy.TXT file and returns a slice of words
 func readWords() ([]string, error) {
-	data, err := happyFS.ReadFile("Happy.TXT")
+	f, err := happyFS.Open("Happy.TXT")
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+
+	return ParseWords(f)
+}
 
+// ParseWords reads a wordlist with one entry per line, skipping blank lines.
+func ParseWords(r io.Reader) ([]string, error) {
 	var words []string
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" {
@@ -90,28 +146,115 @@ func (g *God) Speak() string {
 		return ""
 	}
 
-	g.mu.RLock()
-	amount := g.amount
-	g.mu.RUnlock()
-
-	return g.generateMessage(amount)
+	return g.generateMessage(g.GetAmount())
 }
 
 // generateMessage generates a message with the specified amount of words
 func (g *God) generateMessage(amount int) string {
-	selectedWords := make([]string, 0, amount)
+	indices := indexPool.Get().(*[]int)
+	*indices = g.pick((*indices)[:0], amount)
+	message := g.join(*indices)
+	indexPool.Put(indices)
+	return message
+}
+
+// generateWords selects the specified amount of random words
+func (g *God) generateWords(amount int) []string {
+	indices := indexPool.Get().(*[]int)
+	*indices = g.pick((*indices)[:0], amount)
+	words := g.wordsAt(*indices)
+	indexPool.Put(indices)
+	return words
+}
+
+// pick appends amount random word indices
+func (g *God) pick(indices []int, amount int) []int {
 	for i := 0; i < amount; i++ {
-		g.mu.Lock()
-		randomIndex := g.rng.Intn(len(g.words))
-		word := g.words[randomIndex]
-		g.mu.Unlock()
+		indices = append(indices, g.src.IntN(len(g.words)))
+	}
+	return indices
+}
 
-		if word != "" {
-			selectedWords = append(selectedWords, word)
+// pickSeeded appends amount word indices drawn from a RNG seeded with seed
+func (g *God) pickSeeded(indices []int, amount int, seed int64) []int {
+	rng := seededPool.Get().(*rand.Rand)
+	rng.Seed(seed)
+	for i := 0; i < amount; i++ {
+		indices = append(indices, rng.Intn(len(g.words)))
+	}
+	seededPool.Put(rng)
+	return indices
+}
+
+// join joins the words at indices with spaces, allocating only the message
+func (g *God) join(indices []int) string {
+	switch len(indices) {
+	case 0:
+		return ""
+	case 1:
+		return g.words[indices[0]]
+	}
+	size := len(indices) - 1
+	for _, i := range indices {
+		size += len(g.words[i])
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+	for n, i := range indices {
+		if n > 0 {
+			b.WriteByte(' ')
 		}
+		b.WriteString(g.words[i])
+	}
+	return b.String()
+}
+
+// wordsAt returns the words at indices
+func (g *God) wordsAt(indices []int) []string {
+	words := make([]string, len(indices))
+	for n, i := range indices {
+		words[n] = g.words[i]
+	}
+	return words
+}
+
+// SpeakSeeded generates a deterministic message: the same seed, amount and
+// wordlist always produce the same words. Nearby seeds such as 1, 2 and 3
+// start with correlated words, so draw seeds at random, or from a RNG
+// seeded once, to get a series of independent messages.
+func (g *God) SpeakSeeded(amount int, seed int64) (string, error) {
+	if err := validateAmount(amount); err != nil {
+		return "", err
+	}
+
+	if len(g.words) == 0 {
+		return "", nil
+	}
+
+	indices := indexPool.Get().(*[]int)
+	*indices = g.pickSeeded((*indices)[:0], amount, seed)
+	message := g.join(*indices)
+	indexPool.Put(indices)
+	return message, nil
+}
+
+// SpeakSeededWords is like SpeakSeeded but returns the selected words
+// instead of joining them into a message.
+func (g *God) SpeakSeededWords(amount int, seed int64) ([]string, error) {
+	if err := validateAmount(amount); err != nil {
+		return nil, err
 	}
 
-	return strings.Join(selectedWords, " ")
+	if len(g.words) == 0 {
+		return nil, nil
+	}
+
+	indices := indexPool.Get().(*[]int)
+	*indices = g.pickSeeded((*indices)[:0], amount, seed)
+	words := g.wordsAt(*indices)
+	indexPool.Put(indices)
+	return words, nil
 }
 
 // SpeakWithAmount generates a random message with a specific amount of words.
@@ -127,26 +270,43 @@ func (g *God) SpeakWithAmount(amount int) (string, error) {
 	return g.generateMessage(amount), nil
 }
 
+// SpeakWords is like SpeakWithAmount but returns the selected words instead
+// of joining them into a message.
+func (g *God) SpeakWords(amount int) ([]string, error) {
+	if err := validateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	if len(g.words) == 0 {
+		return nil, nil
+	}
+
+	return g.generateWords(amount), nil
+}
+
 // SetAmount sets the number of words to generate.
 func (g *God) SetAmount(amount int) error {
 	if err := validateAmount(amount); err != nil {
 		return err
 	}
 
-	g.mu.Lock()
-	g.amount = amount
-	g.mu.Unlock()
+	g.amount.Store(int64(amount))
 	return nil
 }
 
 // GetAmount returns the current amount of words to generate.
 func (g *God) GetAmount() int {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-	return g.amount
+	return int(g.amount.Load())
 }
 
 // GetWordsCount returns the total number of words available
 func (g *God) GetWordsCount() int {
 	return len(g.words)
 }
+
+// Words returns a copy of the wordlist God speaks from.
+func (g *God) Words() []string {
+	words := make([]string, len(g.words))
+	copy(words, g.words)
+	return words
+}
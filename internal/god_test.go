@@ -1,6 +1,12 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -226,6 +232,7 @@ func BenchmarkGodSpeak(b *testing.B) {
 		b.Fatalf("Failed to create God instance: %v", err)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_ = god.Speak()
@@ -247,9 +254,29 @@ func BenchmarkGodSpeakWithAmount(b *testing.B) {
 		b.Fatalf("Failed to create God instance: %v", err)
 	}
 
+	for _, amount := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("amount=%d", amount), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := god.SpeakWithAmount(amount)
+				if err != nil {
+					b.Fatalf("Failed to speak: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGodSpeakSeeded(b *testing.B) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		b.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := god.SpeakWithAmount(10)
+		_, err := god.SpeakSeeded(DefaultAmount, int64(i))
 		if err != nil {
 			b.Fatalf("Failed to speak: %v", err)
 		}
@@ -262,6 +289,7 @@ func BenchmarkGodConcurrentSpeak(b *testing.B) {
 		b.Fatalf("Failed to create God instance: %v", err)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
@@ -269,3 +297,198 @@ func BenchmarkGodConcurrentSpeak(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkGodConcurrentSpeakSeeded(b *testing.B) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		b.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		seed := int64(0)
+		for pb.Next() {
+			seed++
+			_, _ = god.SpeakSeeded(DefaultAmount, seed)
+		}
+	})
+}
+
+func TestGodSpeakSeeded(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	first, err := god.SpeakSeeded(20, 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := god.SpeakSeeded(20, 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected identical messages for the same seed, got %q and %q", first, second)
+	}
+
+	other, err := god.SpeakSeeded(20, 43)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first == other {
+		t.Errorf("Expected different messages for different seeds, got %q", first)
+	}
+
+	// pooled RNGs must keep the words a fresh RNG picks, or permalinks break
+	rng := rand.New(rand.NewSource(42))
+	words := make([]string, 20)
+	for i := range words {
+		words[i] = god.words[rng.Intn(len(god.words))]
+	}
+	if expected := strings.Join(words, " "); first != expected {
+		t.Errorf("Expected %q, got %q", expected, first)
+	}
+
+	if _, err := god.SpeakSeeded(0, 42); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestGodSpeakWords(t *testing.T) {
+	god, err := NewGod(DefaultAmount)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	words, err := god.SpeakSeededWords(7, 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(words) != 7 {
+		t.Errorf("Expected 7 words, got %d", len(words))
+	}
+	message, _ := god.SpeakSeeded(7, 42)
+	if strings.Join(words, " ") != message {
+		t.Errorf("Expected the words of %q, got %q", message, words)
+	}
+
+	words, err = god.SpeakWords(5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(words) != 5 {
+		t.Errorf("Expected 5 words, got %d", len(words))
+	}
+
+	if _, err := god.SpeakWords(0); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := god.SpeakSeededWords(MaxAmount+1, 42); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func FuzzParseWords(f *testing.F) {
+	f.Add("Catastrophic Success\nI'll ask nicely\n")
+	f.Add("  padded  \r\n\n\n\tword\t\n")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		words, err := ParseWords(strings.NewReader(input))
+		if err != nil {
+			// only lines longer than the scanner buffer are rejected
+			return
+		}
+
+		for _, word := range words {
+			if word == "" || word != strings.TrimSpace(word) || strings.Contains(word, "\n") {
+				t.Fatalf("Expected trimmed, non-empty single line entries, got %q", word)
+			}
+		}
+
+		// a wordlist written one entry per line parses back unchanged
+		again, err := ParseWords(strings.NewReader(strings.Join(words, "\n")))
+		if err != nil || !slices.Equal(words, again) {
+			t.Fatalf("Expected %q to round-trip, got %q, %v", words, again, err)
+		}
+
+		god, err := NewGodWithWords(words, 1)
+		if len(words) == 0 {
+			if !errors.Is(err, ErrEmptyWordlist) {
+				t.Fatalf("Expected ErrEmptyWordlist, got %v", err)
+			}
+			return
+		}
+		if err != nil || !slices.Equal(god.Words(), words) {
+			t.Fatalf("Expected God to speak from %q, got %v", words, err)
+		}
+	})
+}
+
+func TestGodConcurrentSetAmount(t *testing.T) {
+	god, err := NewGodWithWords([]string{"alpha", "beta", "gamma"}, 10)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if words := strings.Fields(god.Speak()); len(words) < 1 || len(words) > 10 {
+					t.Errorf("Expected 1 to 10 words, got %d", len(words))
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				god.SetAmount(j%10 + 1)
+				god.SpeakSeeded(j%10+1, int64(j))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// lockedSource is a single reproducible generator shared under a lock
+type lockedSource struct {
+	mu  sync.Mutex
+	rng *randv2.Rand
+}
+
+// IntN returns the next int in [0, n) of the generator
+func (s *lockedSource) IntN(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.IntN(n)
+}
+
+// seedGod makes the words god picks reproducible: a single caller gets the
+// same sequence of messages for the same seed on any machine
+func seedGod(god *God, seed int64) {
+	god.src = &lockedSource{rng: randv2.New(randv2.NewPCG(uint64(seed), 0))}
+}
+
+func TestGodSeed(t *testing.T) {
+	// seeded messages must not depend on the machine, e.g. its processors
+	messages := make([][]string, 2)
+	for i, procs := range []int{1, 4} {
+		defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+		god, err := NewGod(DefaultAmount)
+		if err != nil {
+			t.Fatalf("Failed to create God instance: %v", err)
+		}
+		seedGod(god, 42)
+		for j := 0; j < 10; j++ {
+			messages[i] = append(messages[i], god.Speak())
+		}
+	}
+	if !slices.Equal(messages[0], messages[1]) {
+		t.Errorf("Expected the same messages for seed 42, got %q and %q", messages[0], messages[1])
+	}
+}
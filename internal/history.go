@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHistoryLimit is the number of history entries returned per page
+	DefaultHistoryLimit = 20
+	// MaxHistoryLimit bounds the page size of history queries
+	MaxHistoryLimit = 100
+	// MaxHistoryEntrySize bounds the size of a stored entry in bytes
+	MaxHistoryEntrySize = 1 << 20
+)
+
+// ErrInvalidHistoryQuery is returned for malformed history queries
+var ErrInvalidHistoryQuery = errors.New("invalid history query")
+
+// ErrHistoryEntryTooLarge is returned when an entry exceeds MaxHistoryEntrySize
+var ErrHistoryEntryTooLarge = errors.New("history entry too large")
+
+// idEncoding encodes entry IDs in lowercase without padding
+var idEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// HistoryEntry is a generated message together with how it was generated
+type HistoryEntry struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	// Source is the program that generated the message, e.g. cli or server
+	Source string `json:"source"`
+	// Kind is how the message was generated, e.g. speak, today or ask
+	Kind     string   `json:"kind"`
+	GodSays  string   `json:"god_says"`
+	Words    []string `json:"words,omitempty"`
+	List     string   `json:"list,omitempty"`
+	Amount   int      `json:"amount"`
+	Seed     *int64   `json:"seed,omitempty"`
+	Question string   `json:"question,omitempty"`
+}
+
+// HistoryQuery selects history entries, newest first
+type HistoryQuery struct {
+	// Search matches messages and questions case-insensitively
+	Search string
+	// OmitQuestions leaves questions out of the search and the returned
+	// entries, for history shown to others
+	OmitQuestions bool
+	// Since and Until bound the entry times when not zero
+	Since time.Time
+	Until time.Time
+	// Offset skips matching entries; Limit caps the page size
+	Offset int
+	Limit  int
+}
+
+// HistoryPage is one page of history query results
+type HistoryPage struct {
+	Entries []HistoryEntry `json:"entries"`
+	// Total counts all matching entries across pages
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// History is an append-only store of generated messages, kept as one JSON
+// document per line so several processes can append to the same file.
+type History struct {
+	mu   sync.Mutex
+	path string
+	// maxEntries caps the stored entries, 0 keeps all of them
+	maxEntries int
+	// count is the number of entries in the file, -1 until it is read
+	count int
+}
+
+// OpenHistory opens the history file at path, creating its directory
+func OpenHistory(path string) (*History, error) {
+	if path == "" {
+		return nil, fmt.Errorf("history file path is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &History{path: path, count: -1}, nil
+}
+
+// SetMaxEntries keeps only the newest n entries, 0 keeps all of them. The
+// file is compacted once it holds a tenth more than n, so entries appended
+// by other processes while it is rewritten may be lost.
+func (h *History) SetMaxEntries(n int) error {
+	if n < 0 {
+		return fmt.Errorf("history limit must not be negative")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxEntries = n
+	return nil
+}
+
+// Path returns the history file path
+func (h *History) Path() string {
+	return h.path
+}
+
+// NewHistoryID returns a random short ID for a history entry
+func NewHistoryID() string {
+	var b [5]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return idEncoding.EncodeToString(b[:])
+}
+
+// SeedHistoryID returns the ID of the message seed reproduces with amount
+// words of list, so the same message always gets the same ID
+func SeedHistoryID(list string, amount int, seed int64) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d\x00%d", list, amount, seed))
+	return idEncoding.EncodeToString(sum[:5])
+}
+
+// Add appends entry, filling in its ID and time when unset, and returns the
+// stored entry
+func (h *History) Add(entry HistoryEntry) (HistoryEntry, error) {
+	if entry.ID == "" {
+		entry.ID = NewHistoryID()
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	if len(line)+1 > MaxHistoryEntrySize {
+		return entry, fmt.Errorf("%w: %d bytes, at most %d", ErrHistoryEntryTooLarge, len(line)+1, MaxHistoryEntrySize)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return entry, err
+	}
+	// a single write keeps lines from concurrent processes intact
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return entry, err
+	}
+	if err := f.Close(); err != nil {
+		return entry, err
+	}
+
+	if h.maxEntries == 0 {
+		return entry, nil
+	}
+	if h.count < 0 {
+		entries, err := h.read()
+		if err != nil {
+			return entry, err
+		}
+		h.count = len(entries)
+	} else {
+		h.count++
+	}
+	if h.count > h.maxEntries+h.maxEntries/10 {
+		if err := h.compact(); err != nil {
+			return entry, fmt.Errorf("failed to compact history: %w", err)
+		}
+	}
+	return entry, nil
+}
+
+// compact rewrites the file with the newest maxEntries entries
+func (h *History) compact() error {
+	entries, err := h.read()
+	if err != nil {
+		return err
+	}
+	entries = entries[max(len(entries)-h.maxEntries, 0):]
+
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), h.path); err != nil {
+		return err
+	}
+	h.count = len(entries)
+	return nil
+}
+
+// Query returns the page of entries matching q, newest first
+func (h *History) Query(q HistoryQuery) (HistoryPage, error) {
+	if q.Offset < 0 || q.Limit < 0 || q.Limit > MaxHistoryLimit {
+		return HistoryPage{}, fmt.Errorf("%w: offset must not be negative and limit must be between 1 and %d", ErrInvalidHistoryQuery, MaxHistoryLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultHistoryLimit
+	}
+
+	entries, err := h.read()
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	search := strings.ToLower(q.Search)
+	var matches []HistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !entry.Time.Before(q.Until) {
+			continue
+		}
+		if q.OmitQuestions {
+			entry.Question = ""
+		}
+		if search != "" && !strings.Contains(strings.ToLower(entry.GodSays), search) && !strings.Contains(strings.ToLower(entry.Question), search) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	page := HistoryPage{Entries: []HistoryEntry{}, Total: len(matches), Offset: q.Offset, Limit: q.Limit}
+	if q.Offset < len(matches) {
+		page.Entries = matches[q.Offset:min(q.Offset+q.Limit, len(matches))]
+	}
+	return page, nil
+}
+
+// Get returns the entry with id
+func (h *History) Get(id string) (HistoryEntry, bool, error) {
+	entries, err := h.read()
+	if err != nil {
+		return HistoryEntry{}, false, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ID == id {
+			return entries[i], true, nil
+		}
+	}
+	return HistoryEntry{}, false, nil
+}
+
+// read loads every entry in file order. Malformed lines, such as one cut
+// short by a crash, and lines over MaxHistoryEntrySize are skipped.
+func (h *History) read() ([]HistoryEntry, error) {
+	f, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		entries []HistoryEntry
+		line    []byte
+		tooLong bool
+	)
+	r := bufio.NewReader(f)
+	for {
+		// read the line in chunks, dropping it once it grows too long
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(chunk) > MaxHistoryEntrySize {
+				line, tooLong = line[:0], true
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		var entry HistoryEntry
+		if !tooLong && json.Unmarshal(line, &entry) == nil {
+			entries = append(entries, entry)
+		}
+		line, tooLong = line[:0], false
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+	}
+}
+
+// ParseSince parses the start of a history range: a YYYY-MM-DD date in loc,
+// an RFC 3339 time, or a duration before now such as 36h or 7d
+func ParseSince(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	if date, err := ParseDay(value, loc); err == nil {
+		return date, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		// days are bounded like durations, larger counts overflow
+		if n, err := strconv.ParseInt(days, 10, 64); err == nil && n >= 0 && n <= int64(math.MaxInt64/(24*time.Hour)) {
+			return now.AddDate(0, 0, -int(n)), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: since must be a YYYY-MM-DD date, an RFC 3339 time or a duration like 7d", ErrInvalidHistoryQuery)
+}
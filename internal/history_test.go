@@ -0,0 +1,305 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestHistoryAddQuery(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "godsays", "history.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	// an empty history has no entries
+	page, err := history.Query(HistoryQuery{})
+	if err != nil || page.Total != 0 || len(page.Entries) != 0 {
+		t.Errorf("Expected an empty history, got %+v, %v", page, err)
+	}
+
+	start := time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC)
+	seed := int64(42)
+	messages := []string{"Merry Christmas", "happy birthday", "Christmas tree", "okay"}
+	for i, message := range messages {
+		entry, err := history.Add(HistoryEntry{Time: start.Add(time.Duration(i) * 24 * time.Hour), Source: "cli", Kind: "speak", GodSays: message, Amount: 2, Seed: &seed})
+		if err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+		if len(entry.ID) != 8 {
+			t.Errorf("Expected an 8 character ID, got %q", entry.ID)
+		}
+	}
+
+	page, err = history.Query(HistoryQuery{Search: "christmas"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if page.Total != 2 || page.Entries[0].GodSays != "Christmas tree" || page.Entries[1].GodSays != "Merry Christmas" {
+		t.Errorf("Expected both Christmas messages, newest first, got %+v", page.Entries)
+	}
+	if page.Entries[0].Seed == nil || *page.Entries[0].Seed != 42 {
+		t.Error("Expected the seed to be stored")
+	}
+
+	page, _ = history.Query(HistoryQuery{Since: start.Add(36 * time.Hour)})
+	if page.Total != 2 {
+		t.Errorf("Expected 2 entries since the 26th, got %d", page.Total)
+	}
+	page, _ = history.Query(HistoryQuery{Until: start.Add(time.Hour)})
+	if page.Total != 1 || page.Entries[0].GodSays != "Merry Christmas" {
+		t.Errorf("Expected only the first entry, got %+v", page.Entries)
+	}
+
+	page, _ = history.Query(HistoryQuery{Offset: 1, Limit: 2})
+	if page.Total != 4 || len(page.Entries) != 2 || page.Entries[0].GodSays != "Christmas tree" {
+		t.Errorf("Expected the second page, got %+v", page)
+	}
+	page, _ = history.Query(HistoryQuery{Offset: 10})
+	if page.Total != 4 || len(page.Entries) != 0 {
+		t.Errorf("Expected an empty page past the end, got %+v", page)
+	}
+
+	if _, err := history.Add(HistoryEntry{Time: start, Kind: "ask", GodSays: "okay", Amount: 1, Question: "Is it Christmas?"}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	page, _ = history.Query(HistoryQuery{Search: "is it"})
+	if page.Total != 1 || page.Entries[0].Question != "Is it Christmas?" {
+		t.Errorf("Expected the question to match, got %+v", page.Entries)
+	}
+	page, _ = history.Query(HistoryQuery{Search: "is it", OmitQuestions: true})
+	if page.Total != 0 {
+		t.Errorf("Expected questions not to match with OmitQuestions, got %+v", page.Entries)
+	}
+	page, _ = history.Query(HistoryQuery{Search: "okay", OmitQuestions: true})
+	if page.Total != 2 || page.Entries[0].Question != "" {
+		t.Errorf("Expected questions to be left out with OmitQuestions, got %+v", page.Entries)
+	}
+
+	if _, err := history.Query(HistoryQuery{Limit: MaxHistoryLimit + 1}); !errors.Is(err, ErrInvalidHistoryQuery) {
+		t.Errorf("Expected ErrInvalidHistoryQuery, got %v", err)
+	}
+
+	entries, _ := history.read()
+	entry, ok, err := history.Get(entries[1].ID)
+	if err != nil || !ok || entry.GodSays != "happy birthday" {
+		t.Errorf("Expected to find the entry by ID, got %+v, %v, %v", entry, ok, err)
+	}
+	if _, ok, _ := history.Get("missing"); ok {
+		t.Error("Expected no entry for an unknown ID")
+	}
+}
+
+func TestHistorySkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := OpenHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	if _, err := history.Add(HistoryEntry{GodSays: "first"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a line cut short by a crash
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":"abc","god_sa` + "\n")
+	f.Close()
+
+	if _, err := history.Add(HistoryEntry{GodSays: "second"}); err != nil {
+		t.Fatal(err)
+	}
+	page, err := history.Query(HistoryQuery{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("Expected 2 entries, got %+v", page.Entries)
+	}
+}
+
+func TestHistorySkipsLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := OpenHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	if _, err := history.Add(HistoryEntry{GodSays: "first"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a line written by another program, too long to be an entry
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"god_says":"` + strings.Repeat("a", 2*MaxHistoryEntrySize) + `"}` + "\n")
+	f.Close()
+
+	if _, err := history.Add(HistoryEntry{GodSays: strings.Repeat("a", MaxHistoryEntrySize)}); !errors.Is(err, ErrHistoryEntryTooLarge) {
+		t.Errorf("Expected ErrHistoryEntryTooLarge, got %v", err)
+	}
+	second, err := history.Add(HistoryEntry{GodSays: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := history.Query(HistoryQuery{})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if page.Total != 2 || page.Entries[0].GodSays != "second" || page.Entries[1].GodSays != "first" {
+		t.Errorf("Expected the 2 short entries, got %d entries", page.Total)
+	}
+	if _, ok, err := history.Get(second.ID); !ok || err != nil {
+		t.Errorf("Expected to get %q after a long line, got %v, %v", second.ID, ok, err)
+	}
+}
+
+func TestHistoryConcurrentAdd(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := history.Add(HistoryEntry{GodSays: "concurrent"}); err != nil {
+				t.Errorf("Failed to add entry: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	page, _ := history.Query(HistoryQuery{})
+	if page.Total != 50 {
+		t.Errorf("Expected 50 entries, got %d", page.Total)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"2024-12-20", time.Date(2024, 12, 20, 0, 0, 0, 0, berlin)},
+		{"2024-12-20T08:00:00Z", time.Date(2024, 12, 20, 8, 0, 0, 0, time.UTC)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"36h", now.Add(-36 * time.Hour)},
+	}
+	for _, tc := range testCases {
+		got, err := ParseSince(tc.value, now, berlin)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tc.value, err)
+			continue
+		}
+		if !got.Equal(tc.expected) {
+			t.Errorf("Expected %v for %q, got %v", tc.expected, tc.value, got)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "-3d", "2024-13-01", "9223372036854775807d"} {
+		if _, err := ParseSince(value, now, berlin); !errors.Is(err, ErrInvalidHistoryQuery) {
+			t.Errorf("Expected ErrInvalidHistoryQuery for %q, got %v", value, err)
+		}
+	}
+}
+
+func FuzzParseSince(f *testing.F) {
+	f.Add("2024-12-20")
+	f.Add("2024-12-20T08:00:00+01:00")
+	f.Add("7d")
+	f.Add("36h")
+	f.Add("-3d")
+
+	now := time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, value string) {
+		since, err := ParseSince(value, now, time.UTC)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidHistoryQuery) {
+				t.Fatalf("Expected ErrInvalidHistoryQuery for %q, got %v", value, err)
+			}
+			return
+		}
+		// durations never reach into the future
+		_, dateErr := ParseDay(value, time.UTC)
+		_, timeErr := time.Parse(time.RFC3339, value)
+		if dateErr != nil && timeErr != nil && since.After(now) {
+			t.Fatalf("Expected the duration %q to reach back from now, got %v", value, since)
+		}
+	})
+}
+
+func FuzzHistoryRoundTrip(f *testing.F) {
+	f.Add("Merry Christmas", "Will it snow?", int64(42))
+	f.Add("quote \" and \\ backslash\nnewline", "", int64(-1))
+	f.Add("", " \x00", int64(0))
+
+	f.Fuzz(func(t *testing.T, message, question string, seed int64) {
+		if !utf8.ValidString(message) || !utf8.ValidString(question) {
+			// JSON replaces invalid UTF-8
+			return
+		}
+		history, err := OpenHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+		if err != nil {
+			t.Fatalf("Failed to open history: %v", err)
+		}
+		added, err := history.Add(HistoryEntry{Kind: "ask", GodSays: message, Words: strings.Fields(message), Amount: 1, Seed: &seed, Question: question})
+		if err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+
+		entry, ok, err := history.Get(added.ID)
+		if err != nil || !ok {
+			t.Fatalf("Expected to read back %q, got %v, %v", added.ID, ok, err)
+		}
+		if entry.GodSays != message || entry.Question != question || *entry.Seed != seed || !entry.Time.Equal(added.Time) || !slices.Equal(entry.Words, added.Words) {
+			t.Fatalf("Expected %+v to round-trip, got %+v", added, entry)
+		}
+	})
+}
+
+func TestHistoryMaxEntries(t *testing.T) {
+	history, err := OpenHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	if err := history.SetMaxEntries(-1); err == nil {
+		t.Error("Expected error for a negative limit, got none")
+	}
+	if err := history.SetMaxEntries(10); err != nil {
+		t.Fatalf("Failed to set limit: %v", err)
+	}
+
+	for i := range 25 {
+		if _, err := history.Add(HistoryEntry{Kind: "speak", GodSays: strconv.Itoa(i), Amount: 1}); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+		// the file never grows past a tenth over the limit
+		if entries, _ := history.read(); len(entries) > 11 {
+			t.Fatalf("Expected at most 11 entries, got %d", len(entries))
+		}
+	}
+
+	entries, _ := history.read()
+	if last := entries[len(entries)-1]; last.GodSays != "24" {
+		t.Errorf("Expected the newest entry to be kept, got %+v", last)
+	}
+	if first, _ := strconv.Atoi(entries[0].GodSays); first != 25-len(entries) {
+		t.Errorf("Expected the oldest entries to be dropped, got %+v", entries[0])
+	}
+}
@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TempleOS palette indexes
+const (
+	Black uint8 = iota
+	Blue
+	Green
+	Cyan
+	Red
+	Purple
+	Brown
+	LightGray
+	DarkGray
+	LightBlue
+	LightGreen
+	LightCyan
+	LightRed
+	LightPurple
+	Yellow
+	White
+)
+
+// TemplePalette is the 16-color palette of TempleOS
+var TemplePalette = color.Palette{
+	color.RGBA{0x00, 0x00, 0x00, 0xFF}, // Black
+	color.RGBA{0x00, 0x00, 0xAA, 0xFF}, // Blue
+	color.RGBA{0x00, 0xAA, 0x00, 0xFF}, // Green
+	color.RGBA{0x00, 0xAA, 0xAA, 0xFF}, // Cyan
+	color.RGBA{0xAA, 0x00, 0x00, 0xFF}, // Red
+	color.RGBA{0xAA, 0x00, 0xAA, 0xFF}, // Purple
+	color.RGBA{0xAA, 0x55, 0x00, 0xFF}, // Brown
+	color.RGBA{0xAA, 0xAA, 0xAA, 0xFF}, // LightGray
+	color.RGBA{0x55, 0x55, 0x55, 0xFF}, // DarkGray
+	color.RGBA{0x55, 0x55, 0xFF, 0xFF}, // LightBlue
+	color.RGBA{0x55, 0xFF, 0x55, 0xFF}, // LightGreen
+	color.RGBA{0x55, 0xFF, 0xFF, 0xFF}, // LightCyan
+	color.RGBA{0xFF, 0x55, 0x55, 0xFF}, // LightRed
+	color.RGBA{0xFF, 0x55, 0xFF, 0xFF}, // LightPurple
+	color.RGBA{0xFF, 0xFF, 0x55, 0xFF}, // Yellow
+	color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, // White
+}
+
+// Theme is a pair of background and foreground palette indexes
+type Theme struct {
+	Background uint8
+	Foreground uint8
+}
+
+// Themes are the named color themes available to renderers
+var Themes = map[string]Theme{
+	"temple":   {Background: White, Foreground: Blue},
+	"dark":     {Background: Black, Foreground: LightGray},
+	"terminal": {Background: Black, Foreground: LightGreen},
+	"bsod":     {Background: Blue, Foreground: White},
+	"gold":     {Background: Black, Foreground: Yellow},
+}
+
+// Palettes are the named word coloring schemes. "mono" draws every word in
+// the theme foreground; "rainbow" cycles words through the TempleOS palette.
+var Palettes = []string{"mono", "rainbow"}
+
+const (
+	// DefaultImageWidth is the default rendered image width in pixels
+	DefaultImageWidth = 640
+	// MinImageWidth and MaxImageWidth bound the rendered image width
+	MinImageWidth = 64
+	MaxImageWidth = 4096
+	// MaxImageScale is the largest supported font scale
+	MaxImageScale = 8
+	// MinImageColumns is the fewest characters a line must fit
+	MinImageColumns = 6
+	// MaxImagePixels bounds the rendered width times height, so long
+	// messages in narrow images can't exhaust memory
+	MaxImagePixels = 16 << 20
+	// glyphSize is the width and height of a font glyph in pixels
+	glyphSize = 8
+)
+
+// ErrInvalidImageOptions is returned when image options are out of range
+var ErrInvalidImageOptions = errors.New("invalid image options")
+
+// ImageOptions configures rendering of a message as an image
+type ImageOptions struct {
+	// Width is the image width in pixels; the message is wrapped to fit
+	Width int
+	// Scale enlarges every font pixel to Scale x Scale pixels
+	Scale   int
+	Theme   string
+	Palette string
+}
+
+// DefaultImageOptions returns the default TempleOS look
+func DefaultImageOptions() ImageOptions {
+	return ImageOptions{Width: DefaultImageWidth, Scale: 2, Theme: "temple", Palette: "mono"}
+}
+
+// ThemeNames returns the sorted names of the available themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// cell is a character placed on the text grid
+type cell struct {
+	r     rune
+	color uint8
+}
+
+// textLayout is a message wrapped into lines of colored cells
+type textLayout struct {
+	theme Theme
+	lines [][]cell
+	opts  ImageOptions
+}
+
+// validate checks the options and resolves the theme
+func (opts ImageOptions) validate() (Theme, error) {
+	if opts.Width < MinImageWidth || opts.Width > MaxImageWidth {
+		return Theme{}, fmt.Errorf("%w: width must be between %d and %d", ErrInvalidImageOptions, MinImageWidth, MaxImageWidth)
+	}
+	if opts.Scale < 1 || opts.Scale > MaxImageScale {
+		return Theme{}, fmt.Errorf("%w: scale must be between 1 and %d", ErrInvalidImageOptions, MaxImageScale)
+	}
+	theme, ok := Themes[opts.Theme]
+	if !ok {
+		return Theme{}, fmt.Errorf("%w: unknown theme %q", ErrInvalidImageOptions, opts.Theme)
+	}
+	if !slices.Contains(Palettes, opts.Palette) {
+		return Theme{}, fmt.Errorf("%w: unknown palette %q", ErrInvalidImageOptions, opts.Palette)
+	}
+	if opts.Width < (MinImageColumns+2)*glyphSize*opts.Scale {
+		return Theme{}, fmt.Errorf("%w: width must be at least %d for scale %d", ErrInvalidImageOptions, (MinImageColumns+2)*glyphSize*opts.Scale, opts.Scale)
+	}
+	return theme, nil
+}
+
+// layoutText wraps message into lines that fit the image width, with a one
+// character margin on every side
+func layoutText(message string, opts ImageOptions) (textLayout, error) {
+	theme, err := opts.validate()
+	if err != nil {
+		return textLayout{}, err
+	}
+
+	columns := opts.Width/(glyphSize*opts.Scale) - 2
+	maxLines := MaxImagePixels/(opts.Width*glyphSize*opts.Scale) - 2
+	layout := textLayout{theme: theme, opts: opts}
+
+	var line []cell
+	for i, word := range strings.Fields(message) {
+		colorIndex := theme.Foreground
+		if opts.Palette == "rainbow" {
+			colorIndex = rainbowColor(i, theme.Background)
+		}
+
+		runes := []rune(word)
+		if len(line) > 0 && len(line)+1+len(runes) > columns {
+			layout.lines = append(layout.lines, line)
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, cell{r: ' ', color: colorIndex})
+		}
+		for _, r := range runes {
+			if len(line) == columns {
+				layout.lines = append(layout.lines, line)
+				line = nil
+			}
+			line = append(line, cell{r: r, color: colorIndex})
+		}
+	}
+	if len(line) > 0 || len(layout.lines) == 0 {
+		layout.lines = append(layout.lines, line)
+	}
+	if len(layout.lines) > maxLines {
+		return textLayout{}, fmt.Errorf("%w: %d lines exceed the %d that fit in %d pixels, use a wider image or smaller scale", ErrInvalidImageOptions, len(layout.lines), maxLines, MaxImagePixels)
+	}
+	return layout, nil
+}
+
+// rainbowColor picks the color of the i-th word, skipping the background and
+// colors too close to it to read
+func rainbowColor(i int, background uint8) uint8 {
+	var colors []uint8
+	for c := uint8(1); c < uint8(len(TemplePalette)); c++ {
+		if c != background && c != LightGray && c != DarkGray && c != White {
+			colors = append(colors, c)
+		}
+	}
+	return colors[i%len(colors)]
+}
+
+// bitmap rasterizes the text at font resolution, one palette index per pixel
+func (l textLayout) bitmap() (pix []uint8, width, height int) {
+	width = l.opts.Width / l.opts.Scale
+	height = (len(l.lines) + 2) * glyphSize
+	pix = make([]uint8, width*height)
+	for i := range pix {
+		pix[i] = l.theme.Background
+	}
+
+	for row, line := range l.lines {
+		for col, c := range line {
+			for gy, bits := range glyph(c.r) {
+				y := (row+1)*glyphSize + gy
+				for gx := 0; gx < glyphSize; gx++ {
+					if bits&(1<<gx) != 0 {
+						pix[y*width+(col+1)*glyphSize+gx] = c.color
+					}
+				}
+			}
+		}
+	}
+	return pix, width, height
+}
+
+// RenderPNG renders message as a paletted PNG in the TempleOS style
+func RenderPNG(w io.Writer, message string, opts ImageOptions) error {
+	layout, err := layoutText(message, opts)
+	if err != nil {
+		return err
+	}
+
+	pix, width, height := layout.bitmap()
+	scale := opts.Scale
+	img := image.NewPaletted(image.Rect(0, 0, opts.Width, height*scale), TemplePalette)
+	for i := range img.Pix {
+		img.Pix[i] = layout.theme.Background
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := pix[y*width+x]
+			for dy := 0; dy < scale; dy++ {
+				offset := img.PixOffset(x*scale, y*scale+dy)
+				for dx := 0; dx < scale; dx++ {
+					img.Pix[offset+dx] = c
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// RenderSVG renders message as an SVG drawing the bitmap font with one path
+// per color, so it stays pixel exact at any zoom
+func RenderSVG(w io.Writer, message string, opts ImageOptions) error {
+	layout, err := layoutText(message, opts)
+	if err != nil {
+		return err
+	}
+
+	pix, width, height := layout.bitmap()
+	paths := make(map[uint8]*strings.Builder)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; {
+			c := pix[y*width+x]
+			run := 1
+			for x+run < width && pix[y*width+x+run] == c {
+				run++
+			}
+			if c != layout.theme.Background {
+				b, ok := paths[c]
+				if !ok {
+					b = &strings.Builder{}
+					paths[c] = b
+				}
+				fmt.Fprintf(b, "M%d %dh%dv1h-%dz", x, y, run, run)
+			}
+			x += run
+		}
+	}
+
+	colors := make([]int, 0, len(paths))
+	for c := range paths {
+		colors = append(colors, int(c))
+	}
+	sort.Ints(colors)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		width*opts.Scale, height*opts.Scale, width, height)
+	b.WriteString("\n")
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, hexColor(layout.theme.Background))
+	b.WriteString("\n")
+	for _, c := range colors {
+		fmt.Fprintf(&b, `<path fill="%s" d="%s"/>`, hexColor(uint8(c)), paths[uint8(c)].String())
+		b.WriteString("\n")
+	}
+	b.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// hexColor returns the CSS hex notation of a palette color
+func hexColor(index uint8) string {
+	r, g, b, _ := TemplePalette[index].RGBA()
+	return fmt.Sprintf("#%02X%02X%02X", r>>8, g>>8, b>>8)
+}
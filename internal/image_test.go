@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLayoutText(t *testing.T) {
+	opts := ImageOptions{Width: 12 * 8, Scale: 1, Theme: "temple", Palette: "mono"}
+	layout, err := layoutText("God says hello supercalifragilistic", opts)
+	if err != nil {
+		t.Fatalf("Failed to lay out text: %v", err)
+	}
+
+	// 12 columns minus the margins leave 10 characters per line
+	expected := []string{"God says", "hello", "supercalif", "ragilistic"}
+	if len(layout.lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d", len(expected), len(layout.lines))
+	}
+	for i, line := range layout.lines {
+		var b strings.Builder
+		for _, c := range line {
+			b.WriteRune(c.r)
+		}
+		if b.String() != expected[i] {
+			t.Errorf("Expected line %d to be %q, got %q", i, expected[i], b.String())
+		}
+	}
+}
+
+func TestLayoutTextRainbow(t *testing.T) {
+	opts := ImageOptions{Width: 640, Scale: 1, Theme: "dark", Palette: "rainbow"}
+	layout, err := layoutText("one two", opts)
+	if err != nil {
+		t.Fatalf("Failed to lay out text: %v", err)
+	}
+
+	line := layout.lines[0]
+	if line[0].color == line[len(line)-1].color {
+		t.Error("Expected alternating words to get different colors")
+	}
+	for _, c := range line {
+		if c.color == Themes["dark"].Background {
+			t.Error("Expected words never to use the background color")
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	var buf bytes.Buffer
+	opts := DefaultImageOptions()
+	if err := RenderPNG(&buf, "God says TempleOS", opts); err != nil {
+		t.Fatalf("Failed to render PNG: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() != opts.Width || bounds.Dy() != 3*8*opts.Scale {
+		t.Errorf("Unexpected image size %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	// The corner is margin, and some pixel of the first glyph is ink
+	if img.At(0, 0) != TemplePalette[White] {
+		t.Errorf("Expected white background, got %v", img.At(0, 0))
+	}
+	found := false
+	for y := 16; y < 32 && !found; y++ {
+		for x := 16; x < 32; x++ {
+			if img.At(x, y) == TemplePalette[Blue] {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected blue text pixels in the first glyph")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	var buf bytes.Buffer
+	opts := ImageOptions{Width: 320, Scale: 2, Theme: "bsod", Palette: "rainbow"}
+	if err := RenderSVG(&buf, "Hello God", opts); err != nil {
+		t.Fatalf("Failed to render SVG: %v", err)
+	}
+
+	var doc struct {
+		Width string `xml:"width,attr"`
+		Rect  struct {
+			Fill string `xml:"fill,attr"`
+		} `xml:"rect"`
+		Paths []struct {
+			Fill string `xml:"fill,attr"`
+		} `xml:"path"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to parse SVG: %v", err)
+	}
+	if doc.Width != "320" {
+		t.Errorf("Expected width 320, got %s", doc.Width)
+	}
+	if doc.Rect.Fill != "#0000AA" {
+		t.Errorf("Expected blue background, got %s", doc.Rect.Fill)
+	}
+	if len(doc.Paths) != 2 {
+		t.Errorf("Expected one path per word color, got %d", len(doc.Paths))
+	}
+}
+
+func TestRenderInvalidOptions(t *testing.T) {
+	testCases := []ImageOptions{
+		{Width: 10, Scale: 1, Theme: "temple", Palette: "mono"},
+		{Width: 640, Scale: 0, Theme: "temple", Palette: "mono"},
+		{Width: 640, Scale: 1, Theme: "neon", Palette: "mono"},
+		{Width: 640, Scale: 1, Theme: "temple", Palette: "plaid"},
+		{Width: 64, Scale: 8, Theme: "temple", Palette: "mono"},
+		{Width: 192, Scale: 8, Theme: "temple", Palette: "mono"},
+	}
+	for _, opts := range testCases {
+		if err := RenderPNG(&bytes.Buffer{}, "God", opts); !errors.Is(err, ErrInvalidImageOptions) {
+			t.Errorf("Expected ErrInvalidImageOptions for %+v, got %v", opts, err)
+		}
+	}
+
+	// a long message in a narrow image would be too many pixels tall
+	long := strings.Repeat("Hallelujah ", MaxAmount)
+	narrow := ImageOptions{Width: 512, Scale: 8, Theme: "temple", Palette: "mono"}
+	for name, render := range map[string]func(io.Writer, string, ImageOptions) error{"png": RenderPNG, "svg": RenderSVG} {
+		if err := render(io.Discard, long, narrow); !errors.Is(err, ErrInvalidImageOptions) {
+			t.Errorf("Expected ErrInvalidImageOptions for a %s of %d pixels, got %v", name, narrow.Width, err)
+		}
+	}
+	if err := RenderPNG(io.Discard, long, DefaultImageOptions()); err != nil {
+		t.Errorf("Expected %d words to fit the default image, got %v", MaxAmount, err)
+	}
+}
+
+func TestGlyphFallback(t *testing.T) {
+	if glyph('λ') != glyph('?') {
+		t.Error("Expected non-ASCII characters to render as '?'")
+	}
+	if glyph(' ') != [8]byte{} {
+		t.Error("Expected space to be blank")
+	}
+}
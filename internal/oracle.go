@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrEmptyQuestion is returned when asking an empty question
+var ErrEmptyQuestion = errors.New("question must not be empty")
+
+// DateSaltLayout is the layout used to turn a date into an answer salt
+const DateSaltLayout = "2006-01-02"
+
+// Ask answers a question deterministically: the same question, in any casing
+// or spacing, always receives the same answer.
+func (g *God) Ask(question string) (string, error) {
+	return g.AskWithSalt(question, "")
+}
+
+// AskOn answers a question with an answer that stays the same for the whole
+// calendar day of t but changes from one day to the next.
+func (g *God) AskOn(question string, t time.Time) (string, error) {
+	return g.AskWithSalt(question, DateSalt(t))
+}
+
+// AskWithSalt answers a question, mixing salt into the derived seed.
+func (g *God) AskWithSalt(question, salt string) (string, error) {
+	seed, err := QuestionSeed(question, salt)
+	if err != nil {
+		return "", err
+	}
+	return g.SpeakSeeded(g.GetAmount(), seed)
+}
+
+// DateSalt returns the salt used by AskOn for the calendar day of t
+func DateSalt(t time.Time) string {
+	return t.Format(DateSaltLayout)
+}
+
+// QuestionSeed derives the seed used to answer a question from a hash of the
+// normalized question and salt.
+func QuestionSeed(question, salt string) (int64, error) {
+	normalized := NormalizeQuestion(question)
+	if normalized == "" {
+		return 0, ErrEmptyQuestion
+	}
+
+	sum := sha256.Sum256([]byte(salt + "\x00" + normalized))
+	return int64(binary.BigEndian.Uint64(sum[:8])), nil
+}
+
+// NormalizeQuestion lowercases a question, collapses whitespace and strips
+// surrounding punctuation so trivially different phrasings get the same answer.
+func NormalizeQuestion(question string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(question)), " ")
+	return strings.TrimFunc(normalized, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+}
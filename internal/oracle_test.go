@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeQuestion(t *testing.T) {
+	testCases := map[string]string{
+		"Will it rain?":          "will it rain",
+		"  will   IT\train ?!  ": "will it rain",
+		"¿Qué pasa?":             "qué pasa",
+		"Is 2+2 = 4?":            "is 2+2 = 4",
+		"...":                    "",
+		"":                       "",
+		"What's \"the\" answer?": "what's \"the\" answer",
+	}
+
+	for question, expected := range testCases {
+		if got := NormalizeQuestion(question); got != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, question, got)
+		}
+	}
+}
+
+func TestGodAsk(t *testing.T) {
+	god, err := NewGod(10)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	answer, err := god.Ask("Will it rain tomorrow?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if answer == "" {
+		t.Fatal("Expected non-empty answer")
+	}
+
+	same, err := god.Ask("  will it RAIN tomorrow ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if same != answer {
+		t.Errorf("Expected the same answer for equivalent questions, got %q and %q", answer, same)
+	}
+
+	other, err := god.Ask("Will it snow tomorrow?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if other == answer {
+		t.Errorf("Expected different answers for different questions, got %q", answer)
+	}
+
+	if _, err := god.Ask(" ?! "); err != ErrEmptyQuestion {
+		t.Errorf("Expected ErrEmptyQuestion, got %v", err)
+	}
+}
+
+func TestGodAskOn(t *testing.T) {
+	god, err := NewGod(10)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	morning := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	evening := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	first, err := god.AskOn("Should I deploy?", morning)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := god.AskOn("Should I deploy?", evening)
+	third, _ := god.AskOn("Should I deploy?", nextDay)
+
+	if first != second {
+		t.Errorf("Expected the same answer within a day, got %q and %q", first, second)
+	}
+	if first == third {
+		t.Errorf("Expected a different answer on the next day, got %q", first)
+	}
+
+	undated, _ := god.Ask("Should I deploy?")
+	if undated == first {
+		t.Errorf("Expected the date salt to change the answer, got %q", first)
+	}
+}
+
+func FuzzNormalizeQuestion(f *testing.F) {
+	f.Add("Will it rain?")
+	f.Add("  WILL   it\train?!  ")
+	f.Add("?!")
+
+	f.Fuzz(func(t *testing.T, question string) {
+		normalized := NormalizeQuestion(question)
+		if again := NormalizeQuestion(normalized); again != normalized {
+			t.Fatalf("Expected normalizing %q to be idempotent, got %q", normalized, again)
+		}
+
+		seed, err := QuestionSeed(question, "")
+		if normalized == "" {
+			if !errors.Is(err, ErrEmptyQuestion) {
+				t.Fatalf("Expected ErrEmptyQuestion for %q, got %v", question, err)
+			}
+			return
+		}
+		if normalizedSeed, _ := QuestionSeed(normalized, ""); err != nil || seed != normalizedSeed {
+			t.Fatalf("Expected %q to get the same answer as %q", question, normalized)
+		}
+	})
+}
@@ -0,0 +1,634 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// QRLevel is a QR code error correction level
+type QRLevel int
+
+// QR code error correction levels, recovering about 7%, 15%, 25% and 30% of
+// damaged codewords
+const (
+	QRLow QRLevel = iota
+	QRMedium
+	QRQuartile
+	QRHigh
+)
+
+const (
+	// MinQRVersion and MaxQRVersion bound the QR code version, which sets
+	// its size to 17 + 4*version modules
+	MinQRVersion = 1
+	MaxQRVersion = 40
+	// QRQuietZone is the light border around a QR code in modules
+	QRQuietZone = 4
+	// DefaultQRScale is the default size of a module in pixels
+	DefaultQRScale = 8
+	// MaxQRScale is the largest supported module size in pixels
+	MaxQRScale = 32
+)
+
+var (
+	// ErrQRTooLong is returned when data does not fit in the largest QR code
+	ErrQRTooLong = errors.New("data too long for a QR code")
+	// ErrInvalidQROptions is returned when QR code options are out of range
+	ErrInvalidQROptions = errors.New("invalid QR code options")
+)
+
+// qrECCCodewordsPerBlock and qrNumECCBlocks are indexed by level and version,
+// from table 9 of ISO/IEC 18004
+var qrECCCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumECCBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrFormatBits are the two bit level indicators of the format information
+var qrFormatBits = [4]int{1, 0, 3, 2}
+
+// ParseQRLevel parses an error correction level name: L, M, Q or H
+func ParseQRLevel(name string) (QRLevel, error) {
+	switch strings.ToUpper(name) {
+	case "L":
+		return QRLow, nil
+	case "M":
+		return QRMedium, nil
+	case "Q":
+		return QRQuartile, nil
+	case "H":
+		return QRHigh, nil
+	}
+	return 0, fmt.Errorf("%w: error correction level must be L, M, Q or H, got %q", ErrInvalidQROptions, name)
+}
+
+// String returns the single letter name of the level
+func (l QRLevel) String() string {
+	return string("LMQH"[l])
+}
+
+// QRCode is an encoded QR code symbol
+type QRCode struct {
+	Version int
+	Level   QRLevel
+	// Size is the width and height in modules, without the quiet zone
+	Size     int
+	modules  []bool
+	function []bool
+}
+
+// EncodeQR encodes data in byte mode into the smallest QR code that holds it
+// at the given error correction level
+func EncodeQR(data []byte, level QRLevel) (*QRCode, error) {
+	if level < QRLow || level > QRHigh {
+		return nil, fmt.Errorf("%w: unknown error correction level %d", ErrInvalidQROptions, level)
+	}
+
+	version := 0
+	for v := MinQRVersion; v <= MaxQRVersion; v++ {
+		if 4+qrCountBits(v)+8*len(data) <= qrDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%w: %d bytes at level %s", ErrQRTooLong, len(data), level)
+	}
+
+	// byte mode indicator, character count and data, then the terminator
+	// and padding up to the capacity of the symbol
+	var bits qrBits
+	bits.append(0x4, 4)
+	bits.append(len(data), qrCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := qrDataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-bits.len))
+	bits.append(0, (8-bits.len%8)%8)
+	for pad := 0xEC; bits.len < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	size := 17 + 4*version
+	q := &QRCode{
+		Version:  version,
+		Level:    level,
+		Size:     size,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+	q.drawFunctionPatterns()
+	q.drawCodewords(qrInterleave(bits.bytes, version, level))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormatBits(best)
+	return q, nil
+}
+
+// Dark reports whether the module at column x and row y is dark. Modules
+// outside the symbol, such as the quiet zone, are light.
+func (q *QRCode) Dark(x, y int) bool {
+	return x >= 0 && x < q.Size && y >= 0 && y < q.Size && q.modules[y*q.Size+x]
+}
+
+// qrBits is a big endian bit buffer
+type qrBits struct {
+	bytes []byte
+	len   int
+}
+
+// append adds the low n bits of value, most significant first
+func (b *qrBits) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if b.len%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>i&1 != 0 {
+			b.bytes[b.len/8] |= 0x80 >> (b.len % 8)
+		}
+		b.len++
+	}
+}
+
+// qrCountBits is the length of the byte mode character count for version
+func qrCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// qrRawModules is the number of modules available for data and error
+// correction codewords, including remainder bits
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// qrDataCodewords is the number of data codewords of a symbol
+func qrDataCodewords(version int, level QRLevel) int {
+	return qrRawModules(version)/8 - qrECCCodewordsPerBlock[level][version]*qrNumECCBlocks[level][version]
+}
+
+// qrInterleave splits data into blocks, appends error correction codewords to
+// each and interleaves the blocks
+func qrInterleave(data []byte, version int, level QRLevel) []byte {
+	numBlocks := qrNumECCBlocks[level][version]
+	eccLen := qrECCCodewordsPerBlock[level][version]
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			// placeholder keeping blocks the same length, skipped below
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of degree, without
+// its leading 1, highest power first
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords of data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// setFunction sets a function module, which masking and data placement skip
+func (q *QRCode) setFunction(x, y int, dark bool) {
+	q.modules[y*q.Size+x] = dark
+	q.function[y*q.Size+x] = true
+}
+
+// qrAlignmentPositions returns the row and column centers of the alignment
+// patterns of version
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	num := version/7 + 2
+	step := (version*8 + num*3 + 5) / (num*4 - 4) * 2
+	result := make([]int, num)
+	result[0] = 6
+	for i, pos := num-1, 17+4*version-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// drawFunctionPatterns draws the timing, finder and alignment patterns and
+// reserves the format and version areas
+func (q *QRCode) drawFunctionPatterns() {
+	size := q.Size
+	for i := 0; i < size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	for _, center := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := center[0]+dx, center[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					dist := max(abs(dx), abs(dy))
+					q.setFunction(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+
+	positions := qrAlignmentPositions(q.Version)
+	last := len(positions) - 1
+	for i, cy := range positions {
+		for j, cx := range positions {
+			// skip the three corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	q.drawFormatBits(0)
+	q.drawVersionBits()
+}
+
+// qrFormatInfo returns the 15 bit BCH protected format information
+func qrFormatInfo(level QRLevel, mask int) int {
+	data := qrFormatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormatBits draws both copies of the format information and the dark
+// module
+func (q *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatInfo(q.Level, mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+	size := q.Size
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, size-15+i, bit(i))
+	}
+	q.setFunction(8, size-8, true)
+}
+
+// qrVersionInfo returns the 18 bit BCH protected version information
+func qrVersionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// drawVersionBits draws both copies of the version information of version 7
+// and up
+func (q *QRCode) drawVersionBits() {
+	if q.Version < 7 {
+		return
+	}
+	bits := qrVersionInfo(q.Version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 != 0
+		a, b := q.Size-11+i%3, i/3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order of the standard,
+// two columns at a time from the bottom right
+func (q *QRCode) drawCodewords(data []byte) {
+	size := q.Size
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < size; vert++ {
+			y := vert
+			if upward {
+				y = size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !q.function[y*size+x] && i < len(data)*8 {
+					q.modules[y*size+x] = data[i/8]>>(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with a mask pattern; applying it twice
+// undoes it
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.function[y*q.Size+x] {
+				q.modules[y*q.Size+x] = !q.modules[y*q.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to scan; the mask with the lowest
+// score is used
+func (q *QRCode) penalty() int {
+	size := q.Size
+	score := 0
+
+	// lines in both directions: runs of five or more modules of one color
+	// and finder-like 1:1:3:1:1 patterns with four light modules on a side
+	line := make([]bool, size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < size; i++ {
+			for j := 0; j < size; j++ {
+				if vertical {
+					line[j] = q.modules[j*size+i]
+				} else {
+					line[j] = q.modules[i*size+j]
+				}
+			}
+			score += qrLinePenalty(line)
+		}
+	}
+
+	// 2x2 blocks of one color
+	for y := 0; y < size-1; y++ {
+		for x := 0; x < size-1; x++ {
+			c := q.modules[y*size+x]
+			if c == q.modules[y*size+x+1] && c == q.modules[(y+1)*size+x] && c == q.modules[(y+1)*size+x+1] {
+				score += 3
+			}
+		}
+	}
+
+	// balance of dark and light modules
+	dark := 0
+	for _, m := range q.modules {
+		if m {
+			dark++
+		}
+	}
+	total := size * size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return score + k*10
+}
+
+// qrFinderLike is the 1:1:3:1:1 finder pattern with four light modules
+var qrFinderLike = []bool{true, false, true, true, true, false, true}
+
+// qrLinePenalty scores runs and finder-like patterns in a row or column
+func qrLinePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+
+	light := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if i >= 0 && i < len(line) && line[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i+len(qrFinderLike) <= len(line); i++ {
+		match := true
+		for j, m := range qrFinderLike {
+			if line[i+j] != m {
+				match = false
+				break
+			}
+		}
+		// the quiet zone counts as light
+		if match && (light(i-4, i) || light(i+7, i+11)) {
+			score += 40
+		}
+	}
+	return score
+}
+
+// abs returns the absolute value of x
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// WritePNG renders the code as a black and white PNG with scale pixels per
+// module and a quiet zone
+func (q *QRCode) WritePNG(w io.Writer, scale int) error {
+	if scale < 1 || scale > MaxQRScale {
+		return fmt.Errorf("%w: scale must be between 1 and %d", ErrInvalidQROptions, MaxQRScale)
+	}
+
+	n := (q.Size + 2*QRQuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, n, n), color.Palette{color.White, color.Black})
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if q.Dark(x/scale-QRQuietZone, y/scale-QRQuietZone) {
+				img.Pix[img.PixOffset(x, y)] = 1
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSVG renders the code as an SVG with scale pixels per module and a
+// quiet zone, drawing the dark modules as a single path
+func (q *QRCode) WriteSVG(w io.Writer, scale int) error {
+	if scale < 1 || scale > MaxQRScale {
+		return fmt.Errorf("%w: scale must be between 1 and %d", ErrInvalidQROptions, MaxQRScale)
+	}
+
+	n := q.Size + 2*QRQuietZone
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		n*scale, n*scale, n, n)
+	b.WriteString("\n")
+	b.WriteString(`<rect width="100%" height="100%" fill="#FFFFFF"/>`)
+	b.WriteString("\n")
+	b.WriteString(`<path fill="#000000" d="`)
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.Dark(x, y) {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x+QRQuietZone, y+QRQuietZone)
+			}
+		}
+	}
+	b.WriteString(`"/>`)
+	b.WriteString("\n</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteTerminal draws the code with unicode half blocks, two modules per
+// character. Light modules are drawn as blocks, which suits terminals with a
+// dark background; invert suits light backgrounds.
+func (q *QRCode) WriteTerminal(w io.Writer, invert bool) error {
+	ink := func(x, y int) bool { return q.Dark(x, y) == invert }
+
+	bw := bufio.NewWriter(w)
+	for y := -QRQuietZone; y < q.Size+QRQuietZone; y += 2 {
+		for x := -QRQuietZone; x < q.Size+QRQuietZone; x++ {
+			top, bottom := ink(x, y), ink(x, y+1)
+			if y+1 >= q.Size+QRQuietZone {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				bw.WriteString("█")
+			case top:
+				bw.WriteString("▀")
+			case bottom:
+				bw.WriteString("▄")
+			default:
+				bw.WriteString(" ")
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" as version 1-M from the standard's worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	ecc := rsRemainder(data, rsDivisor(len(expected)))
+	if !bytes.Equal(ecc, expected) {
+		t.Errorf("Expected error correction codewords %v, got %v", expected, ecc)
+	}
+}
+
+func TestQRFormatInfo(t *testing.T) {
+	expected := map[QRLevel][8]string{
+		QRLow:    {"111011111000100", "111001011110011", "111110110101010", "111100010011101", "110011000101111", "110001100011000", "110110001000001", "110100101110110"},
+		QRMedium: {"101010000010010", "101000100100101", "101111001111100", "101101101001011", "100010111111001", "100000011001110", "100111110010111", "100101010100000"},
+	}
+	for level, masks := range expected {
+		for mask, bits := range masks {
+			var got strings.Builder
+			info := qrFormatInfo(level, mask)
+			for i := 14; i >= 0; i-- {
+				got.WriteByte('0' + byte(info>>i&1))
+			}
+			if got.String() != bits {
+				t.Errorf("Expected format info %s for level %s mask %d, got %s", bits, level, mask, got.String())
+			}
+		}
+	}
+}
+
+func TestQRVersionInfo(t *testing.T) {
+	testCases := map[int]int{7: 0x07C94, 8: 0x085BC, 40: 0x28C69}
+	for version, expected := range testCases {
+		if info := qrVersionInfo(version); info != expected {
+			t.Errorf("Expected version info %#05x for version %d, got %#05x", expected, version, info)
+		}
+	}
+}
+
+func TestQRAlignmentPositions(t *testing.T) {
+	testCases := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		16: {6, 26, 50, 74},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, expected := range testCases {
+		got := qrAlignmentPositions(version)
+		if len(got) != len(expected) {
+			t.Errorf("Expected %v for version %d, got %v", expected, version, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Errorf("Expected %v for version %d, got %v", expected, version, got)
+				break
+			}
+		}
+	}
+}
+
+func TestQRCapacity(t *testing.T) {
+	// byte mode capacities of versions 1, 10 and 40 from the standard
+	testCases := []struct {
+		version int
+		level   QRLevel
+		bytes   int
+	}{
+		{1, QRLow, 17}, {1, QRMedium, 14}, {1, QRQuartile, 11}, {1, QRHigh, 7},
+		{10, QRLow, 271}, {10, QRHigh, 119},
+		{40, QRLow, 2953}, {40, QRMedium, 2331}, {40, QRQuartile, 1663}, {40, QRHigh, 1273},
+	}
+	for _, tc := range testCases {
+		if got := (qrDataCodewords(tc.version, tc.level)*8 - 4 - qrCountBits(tc.version)) / 8; got != tc.bytes {
+			t.Errorf("Expected version %d-%s to hold %d bytes, got %d", tc.version, tc.level, tc.bytes, got)
+		}
+	}
+
+	q, err := EncodeQR(make([]byte, 17), QRLow)
+	if err != nil || q.Version != 1 {
+		t.Errorf("Expected 17 bytes to fit version 1, got %v", err)
+	}
+	q, err = EncodeQR(make([]byte, 18), QRLow)
+	if err != nil || q.Version != 2 || q.Size != 25 {
+		t.Errorf("Expected 18 bytes to need version 2, got %v", err)
+	}
+	if _, err := EncodeQR(make([]byte, 2954), QRLow); !errors.Is(err, ErrQRTooLong) {
+		t.Errorf("Expected ErrQRTooLong, got %v", err)
+	}
+}
+
+// decodeQR reads the data bytes back from a code, checking the format
+// information and the error correction of every block
+func decodeQR(t *testing.T, q *QRCode) []byte {
+	t.Helper()
+
+	var info int
+	for i := 14; i >= 0; i-- {
+		x, y := 8, 8
+		switch {
+		case i <= 5:
+			y = i
+		case i == 6:
+			y = 7
+		case i == 7:
+		case i == 8:
+			x = 7
+		default:
+			x, y = 14-i, 8
+		}
+		info <<= 1
+		if q.Dark(x, y) {
+			info |= 1
+		}
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if qrFormatInfo(q.Level, m) == info {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("Format information %015b does not match level %s", info, q.Level)
+	}
+
+	// read the codewords in placement order with the mask undone
+	clone := &QRCode{Version: q.Version, Level: q.Level, Size: q.Size, modules: append([]bool(nil), q.modules...), function: q.function}
+	clone.applyMask(mask)
+	raw := make([]byte, qrRawModules(q.Version)/8)
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.Size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = q.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if !q.function[y*q.Size+x] && i < len(raw)*8 {
+					if clone.Dark(x, y) {
+						raw[i/8] |= 0x80 >> (i % 8)
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	numBlocks := qrNumECCBlocks[q.Level][q.Version]
+	eccLen := qrECCCodewordsPerBlock[q.Level][q.Version]
+	numShort := numBlocks - len(raw)%numBlocks
+	shortData := len(raw)/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortData+1; i++ {
+		for j := range blocks {
+			if i < shortData || j >= numShort {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for j := range blocks {
+		var own []byte
+		for e := 0; e < eccLen; e++ {
+			own = append(own, raw[k+e*numBlocks+j])
+		}
+		if !bytes.Equal(rsRemainder(blocks[j], rsDivisor(eccLen)), own) {
+			t.Fatalf("Block %d fails error correction", j)
+		}
+		data = append(data, blocks[j]...)
+	}
+
+	if data[0]>>4 != 0x4 {
+		t.Fatalf("Expected byte mode, got %x", data[0]>>4)
+	}
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for ; n > 0; n-- {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	read(4)
+	count := read(qrCountBits(q.Version))
+	out := make([]byte, count)
+	for i := range out {
+		out[i] = byte(read(8))
+	}
+	return out
+}
+
+func TestEncodeQRRoundTrip(t *testing.T) {
+	testCases := []struct {
+		data  string
+		level QRLevel
+	}{
+		{"God says", QRLow},
+		{"https://godsays.example.com/?seed=42&amount=5", QRMedium},
+		{strings.Repeat("hallelujah ", 30), QRQuartile},
+		{strings.Repeat("TempleOS ", 120), QRHigh},
+	}
+	for _, tc := range testCases {
+		q, err := EncodeQR([]byte(tc.data), tc.level)
+		if err != nil {
+			t.Fatalf("Failed to encode %d bytes: %v", len(tc.data), err)
+		}
+		if got := string(decodeQR(t, q)); got != tc.data {
+			t.Errorf("Expected to read back %q, got %q", tc.data, got)
+		}
+
+		// finder pattern centers and the dark module
+		for _, p := range [][2]int{{3, 3}, {q.Size - 4, 3}, {3, q.Size - 4}, {8, q.Size - 8}} {
+			if !q.Dark(p[0], p[1]) {
+				t.Errorf("Expected a dark module at %v in version %d", p, q.Version)
+			}
+		}
+	}
+}
+
+func TestParseQRLevel(t *testing.T) {
+	for name, expected := range map[string]QRLevel{"L": QRLow, "m": QRMedium, "Q": QRQuartile, "h": QRHigh} {
+		if level, err := ParseQRLevel(name); err != nil || level != expected {
+			t.Errorf("Expected level %s for %q, got %s (%v)", expected, name, level, err)
+		}
+	}
+	if _, err := ParseQRLevel("X"); !errors.Is(err, ErrInvalidQROptions) {
+		t.Errorf("Expected ErrInvalidQROptions, got %v", err)
+	}
+}
+
+func TestQRRender(t *testing.T) {
+	q, err := EncodeQR([]byte("God says"), QRMedium)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	n := q.Size + 2*QRQuietZone
+
+	var buf bytes.Buffer
+	if err := q.WritePNG(&buf, 4); err != nil {
+		t.Fatalf("Failed to render PNG: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+	if img.Bounds().Dx() != n*4 {
+		t.Errorf("Expected width %d, got %d", n*4, img.Bounds().Dx())
+	}
+	if r, _, _, _ := img.At(QRQuietZone*4, QRQuietZone*4).RGBA(); r != 0 {
+		t.Error("Expected the finder pattern corner to be black")
+	}
+
+	buf.Reset()
+	if err := q.WriteSVG(&buf, 4); err != nil {
+		t.Fatalf("Failed to render SVG: %v", err)
+	}
+	var doc struct {
+		Width string `xml:"width,attr"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to parse SVG: %v", err)
+	}
+
+	buf.Reset()
+	if err := q.WriteTerminal(&buf, false); err != nil {
+		t.Fatalf("Failed to render terminal: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != (n+1)/2 || len([]rune(lines[0])) != n {
+		t.Errorf("Expected %d lines of %d characters, got %d lines", (n+1)/2, n, len(lines))
+	}
+
+	if err := q.WritePNG(&buf, 0); !errors.Is(err, ErrInvalidQROptions) {
+		t.Errorf("Expected ErrInvalidQROptions, got %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Statistical tests of how God picks words. Every sampling mode draws from a
+// wordlist of distinct words so each word maps back to its index, and the
+// index sequence must pass a chi-square uniformity test, a serial
+// correlation test and a runs test.
+//
+// Random messages are drawn with math/rand/v2 and seeded messages, answers
+// and daily messages with math/rand. Every mode but the Runtime ones takes
+// its randomness from fixed seeds, random messages through seedGod, so those
+// are deterministic on every machine: a failure means the sampling changed,
+// not bad luck. The Runtime modes draw from the runtime's generator that
+// production uses, so with the same bounds each of their runs fails by
+// chance about once in a few thousand.
+//
+// The default samples keep go test fast; run with -long for large samples:
+//
+//	go test ./internal -run Randomness -long
+
+var long = flag.Bool("long", false, "Run the statistical randomness tests with large samples")
+
+// randomnessWords is the size of the wordlist the tests sample from
+const randomnessWords = 64
+
+// zLimit bounds the normal test statistics, a two-sided p of about 0.0001
+const zLimit = 3.9
+
+// sampleSize returns the number of word indices drawn per sampling mode
+func sampleSize() int {
+	if *long {
+		return 2_000_000
+	}
+	return 50_000
+}
+
+// sampler draws n word indices from god, seeding any RNG from seed
+type sampler func(god *God, index map[string]int, n int, seed int64) []int
+
+// samplers are the sampling modes of God
+var samplers = map[string]sampler{
+	"Speak": func(god *God, index map[string]int, n int, seed int64) []int {
+		seedGod(god, seed)
+		god.SetAmount(50)
+		var indices []int
+		for len(indices) < n {
+			indices = appendIndices(indices, index, strings.Fields(god.Speak()))
+		}
+		return indices[:n]
+	},
+	"SpeakWithAmount": func(god *God, index map[string]int, n int, seed int64) []int {
+		seedGod(god, seed)
+		var indices []int
+		for amount := 1; len(indices) < n; amount = amount%MaxAmount + 1 {
+			message, _ := god.SpeakWithAmount(amount)
+			indices = appendIndices(indices, index, strings.Fields(message))
+		}
+		return indices[:n]
+	},
+	"SpeakRuntime": func(god *God, index map[string]int, n int, seed int64) []int {
+		god.SetAmount(50)
+		var indices []int
+		for len(indices) < n {
+			indices = appendIndices(indices, index, strings.Fields(god.Speak()))
+		}
+		return indices[:n]
+	},
+	"SpeakWithAmountRuntime": func(god *God, index map[string]int, n int, seed int64) []int {
+		var indices []int
+		for amount := 1; len(indices) < n; amount = amount%MaxAmount + 1 {
+			message, _ := god.SpeakWithAmount(amount)
+			indices = appendIndices(indices, index, strings.Fields(message))
+		}
+		return indices[:n]
+	},
+	"SpeakSeeded": func(god *God, index map[string]int, n int, seed int64) []int {
+		// seeds drawn from a RNG, as the REPL does.
+		// Consecutive seeds fail: math/rand starts their streams with
+		// correlated values.
+		seeds := rand.New(rand.NewSource(seed))
+		var indices []int
+		for len(indices) < n {
+			words, _ := god.SpeakSeededWords(8, seeds.Int63())
+			indices = appendIndices(indices, index, words)
+		}
+		return indices[:n]
+	},
+	"Ask": func(god *God, index map[string]int, n int, seed int64) []int {
+		var indices []int
+		for i := 0; len(indices) < n; i++ {
+			answer, _ := god.AskWithSalt(fmt.Sprintf("question %d of %d", i, seed), "")
+			indices = appendIndices(indices, index, strings.Fields(answer))
+		}
+		return indices[:n]
+	},
+	"Daily": func(god *God, index map[string]int, n int, seed int64) []int {
+		god.SetAmount(8)
+		date := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		namespace := fmt.Sprintf("namespace-%d", seed)
+		var indices []int
+		for day := 0; len(indices) < n; day++ {
+			message, _ := god.Daily(date.AddDate(0, 0, day), namespace)
+			indices = appendIndices(indices, index, strings.Fields(message))
+		}
+		return indices[:n]
+	},
+}
+
+// appendIndices appends the wordlist index of each word
+func appendIndices(indices []int, index map[string]int, words []string) []int {
+	for _, word := range words {
+		indices = append(indices, index[word])
+	}
+	return indices
+}
+
+// indexedGod returns a God speaking from distinct single-token words and the
+// index of each word
+func indexedGod(t *testing.T) (*God, map[string]int) {
+	words := make([]string, randomnessWords)
+	index := make(map[string]int, randomnessWords)
+	for i := range words {
+		words[i] = fmt.Sprintf("w%d", i)
+		index[words[i]] = i
+	}
+	god, err := NewGodWithWords(words, 8)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+	return god, index
+}
+
+func TestRandomness(t *testing.T) {
+	n := sampleSize()
+	for name, sample := range samplers {
+		t.Run(name, func(t *testing.T) {
+			for _, seed := range []int64{1, 42, 20241225} {
+				god, index := indexedGod(t)
+				indices := sample(god, index, n, seed)
+				if err := checkRandomness(indices, randomnessWords); err != nil {
+					t.Errorf("Seed %d: %v", seed, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomnessHarness(t *testing.T) {
+	// the harness must reject sequences that are obviously not random
+	n := 10_000
+	rng := rand.New(rand.NewSource(1))
+	testCases := map[string]func(i int) int{
+		"biased":      func(i int) int { return rng.Intn(randomnessWords) % (randomnessWords - 1) },
+		"round robin": func(i int) int { return i % randomnessWords },
+		"sticky": func(i int) int {
+			// repeats each pick, uniform but serially correlated
+			if i%2 == 1 {
+				return -1
+			}
+			return rng.Intn(randomnessWords)
+		},
+		"alternating": func(i int) int {
+			// low and high halves alternate, too many runs
+			return rng.Intn(randomnessWords/2) + (i%2)*randomnessWords/2
+		},
+	}
+	for name, next := range testCases {
+		indices := make([]int, n)
+		for i := range indices {
+			if indices[i] = next(i); indices[i] < 0 {
+				indices[i] = indices[i-1]
+			}
+		}
+		if err := checkRandomness(indices, randomnessWords); err == nil {
+			t.Errorf("Expected the %s sequence to fail", name)
+		}
+	}
+
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = rng.Intn(randomnessWords)
+	}
+	if err := checkRandomness(indices, randomnessWords); err != nil {
+		t.Errorf("Expected a math/rand sequence to pass, got %v", err)
+	}
+}
+
+// checkRandomness tests that indices look like independent uniform draws
+// from [0, k)
+func checkRandomness(indices []int, k int) error {
+	chi2 := chiSquare(indices, k)
+	if low, high := chiSquareBounds(k - 1); chi2 < low || chi2 > high {
+		return fmt.Errorf("chi-square %.1f outside [%.1f, %.1f] for %d bins", chi2, low, high, k)
+	}
+	if z := serialCorrelationZ(indices); math.Abs(z) > zLimit {
+		return fmt.Errorf("serial correlation z-score %.2f exceeds %.1f", z, zLimit)
+	}
+	if z := runsZ(indices, k); math.Abs(z) > zLimit {
+		return fmt.Errorf("runs z-score %.2f exceeds %.1f", z, zLimit)
+	}
+	return nil
+}
+
+// chiSquare returns Pearson's statistic of indices against the uniform
+// distribution over k bins
+func chiSquare(indices []int, k int) float64 {
+	counts := make([]int, k)
+	for _, i := range indices {
+		counts[i]++
+	}
+	expected := float64(len(indices)) / float64(k)
+	var chi2 float64
+	for _, count := range counts {
+		d := float64(count) - expected
+		chi2 += d * d / expected
+	}
+	return chi2
+}
+
+// chiSquareBounds returns the range a chi-square statistic with df degrees
+// of freedom stays in with two-sided probability about 0.9999, using the
+// Wilson-Hilferty approximation. Values below the range are too uniform to
+// be random.
+func chiSquareBounds(df int) (float64, float64) {
+	v := 2 / (9 * float64(df))
+	bound := func(z float64) float64 {
+		return float64(df) * math.Pow(1-v+z*math.Sqrt(v), 3)
+	}
+	return bound(-zLimit), bound(zLimit)
+}
+
+// serialCorrelationZ returns the z-score of the lag-1 autocorrelation of
+// indices, which is about normal with mean -1/n and variance 1/n for
+// independent draws
+func serialCorrelationZ(indices []int) float64 {
+	n := float64(len(indices))
+	var mean float64
+	for _, i := range indices {
+		mean += float64(i)
+	}
+	mean /= n
+
+	var num, den float64
+	for i, x := range indices {
+		d := float64(x) - mean
+		den += d * d
+		if i > 0 {
+			num += d * (float64(indices[i-1]) - mean)
+		}
+	}
+	r := num / den
+	return (r + 1/n) * math.Sqrt(n)
+}
+
+// runsZ returns the Wald-Wolfowitz z-score of the runs of indices below and
+// at or above k/2
+func runsZ(indices []int, k int) float64 {
+	var low, high, runs float64
+	for i, x := range indices {
+		if x < k/2 {
+			low++
+		} else {
+			high++
+		}
+		if i == 0 || (x < k/2) != (indices[i-1] < k/2) {
+			runs++
+		}
+	}
+	n := low + high
+	mean := 2*low*high/n + 1
+	variance := (mean - 1) * (mean - 2) / (n - 1)
+	return (runs - mean) / math.Sqrt(variance)
+}
@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultTempo is the default song tempo in beats per minute
+	DefaultTempo = 120
+	// MinTempo and MaxTempo bound the song tempo
+	MinTempo = 40
+	MaxTempo = 300
+	// DefaultSongLength is the default number of notes in a random song
+	DefaultSongLength = 32
+	// MaxSongLength is the maximum number of notes in a song
+	MaxSongLength = 256
+	// MaxSongSeconds bounds the synthesized audio length
+	MaxSongSeconds = 180
+	// SongSampleRate is the sample rate of synthesized songs
+	SongSampleRate = 22050
+)
+
+// ErrInvalidSongOptions is returned when song options are out of range
+var ErrInvalidSongOptions = errors.New("invalid song options")
+
+// Note is a single tone of a God song. A zero frequency is a rest.
+type Note struct {
+	Frequency float64
+	// Beats is the note length in beats
+	Beats float64
+}
+
+// Waveforms are the available oscillator shapes
+var Waveforms = []string{"sine", "square", "triangle"}
+
+// SongOptions configures synthesis of a song
+type SongOptions struct {
+	Tempo    int
+	Waveform string
+}
+
+// DefaultSongOptions returns the default synthesis options
+func DefaultSongOptions() SongOptions {
+	return SongOptions{Tempo: DefaultTempo, Waveform: "square"}
+}
+
+// songScale is a two octave C major pentatonic scale, as MIDI note numbers,
+// which sounds pleasant whatever order the notes come in
+var songScale = []int{60, 62, 64, 67, 69, 72, 74, 76, 79, 81}
+
+// songBeats are the note lengths God chooses from, weighted toward quarters
+var songBeats = []float64{0.5, 0.5, 1, 1, 1, 1, 2}
+
+// midiFrequency returns the frequency of a MIDI note number in Hz
+func midiFrequency(note int) float64 {
+	return 440 * math.Pow(2, float64(note-69)/12)
+}
+
+// GodSong composes a melody of length notes like TempleOS' God song: random
+// notes and lengths with the occasional rest. The same seed always gives the
+// same song.
+func GodSong(seed int64, length int) ([]Note, error) {
+	if length < 1 || length > MaxSongLength {
+		return nil, fmt.Errorf("%w: length must be between 1 and %d", ErrInvalidSongOptions, MaxSongLength)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	notes := make([]Note, length)
+	for i := range notes {
+		notes[i].Beats = songBeats[rng.Intn(len(songBeats))]
+		if rng.Intn(8) != 0 {
+			notes[i].Frequency = midiFrequency(songScale[rng.Intn(len(songScale))])
+		}
+	}
+	return notes, nil
+}
+
+// SongFromMessage maps every word of a message to a note, so a message
+// always sings the same tune. Longer words are held for longer.
+func SongFromMessage(message string) []Note {
+	words := strings.Fields(message)
+	if len(words) > MaxSongLength {
+		words = words[:MaxSongLength]
+	}
+
+	notes := make([]Note, len(words))
+	for i, word := range words {
+		h := fnv.New32a()
+		h.Write([]byte(strings.ToLower(word)))
+		notes[i] = Note{
+			Frequency: midiFrequency(songScale[h.Sum32()%uint32(len(songScale))]),
+			Beats:     songBeats[min(len(word), len(songBeats))-1],
+		}
+	}
+	return notes
+}
+
+// validate checks the synthesis options
+func (opts SongOptions) validate() error {
+	if opts.Tempo < MinTempo || opts.Tempo > MaxTempo {
+		return fmt.Errorf("%w: tempo must be between %d and %d", ErrInvalidSongOptions, MinTempo, MaxTempo)
+	}
+	if !slices.Contains(Waveforms, opts.Waveform) {
+		return fmt.Errorf("%w: unknown waveform %q", ErrInvalidSongOptions, opts.Waveform)
+	}
+	return nil
+}
+
+// oscillate returns the waveform value in [-1, 1] at phase in [0, 1)
+func oscillate(waveform string, phase float64) float64 {
+	switch waveform {
+	case "square":
+		if phase < 0.5 {
+			return 1
+		}
+		return -1
+	case "triangle":
+		return 4*math.Abs(phase-0.5) - 1
+	default:
+		return math.Sin(2 * math.Pi * phase)
+	}
+}
+
+// WriteWAV synthesizes notes into a 16-bit mono PCM WAV file
+func WriteWAV(w io.Writer, notes []Note, opts SongOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	secondsPerBeat := 60 / float64(opts.Tempo)
+	samples := 0
+	for _, note := range notes {
+		samples += int(note.Beats * secondsPerBeat * SongSampleRate)
+	}
+	if samples > MaxSongSeconds*SongSampleRate {
+		return fmt.Errorf("%w: song longer than %d seconds", ErrInvalidSongOptions, MaxSongSeconds)
+	}
+
+	const (
+		bitsPerSample = 16
+		channels      = 1
+		blockAlign    = channels * bitsPerSample / 8
+		volume        = 0.25 * math.MaxInt16
+		// fade is the attack and release time in samples, avoiding clicks
+		fade = SongSampleRate / 200
+	)
+	dataSize := uint32(samples * blockAlign)
+
+	bw := bufio.NewWriter(w)
+	header := []any{
+		[4]byte{'R', 'I', 'F', 'F'}, 36 + dataSize, [4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '}, uint32(16), uint16(1), uint16(channels),
+		uint32(SongSampleRate), uint32(SongSampleRate * blockAlign), uint16(blockAlign), uint16(bitsPerSample),
+		[4]byte{'d', 'a', 't', 'a'}, dataSize,
+	}
+	for _, field := range header {
+		if err := binary.Write(bw, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, 2)
+	for _, note := range notes {
+		n := int(note.Beats * secondsPerBeat * SongSampleRate)
+		phase := 0.0
+		for i := 0; i < n; i++ {
+			var sample float64
+			if note.Frequency > 0 {
+				envelope := math.Min(1, math.Min(float64(i)/fade, float64(n-i)/fade))
+				sample = oscillate(opts.Waveform, phase) * envelope * volume
+				phase += note.Frequency / SongSampleRate
+				phase -= math.Floor(phase)
+			}
+			binary.LittleEndian.PutUint16(buf, uint16(int16(sample)))
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGodSong(t *testing.T) {
+	song, err := GodSong(42, 16)
+	if err != nil {
+		t.Fatalf("Failed to compose song: %v", err)
+	}
+	if len(song) != 16 {
+		t.Fatalf("Expected 16 notes, got %d", len(song))
+	}
+
+	again, _ := GodSong(42, 16)
+	if !reflect.DeepEqual(song, again) {
+		t.Error("Expected the same seed to compose the same song")
+	}
+	other, _ := GodSong(43, 16)
+	if reflect.DeepEqual(song, other) {
+		t.Error("Expected different seeds to compose different songs")
+	}
+
+	for _, note := range song {
+		if note.Beats <= 0 {
+			t.Errorf("Expected positive note length, got %v", note.Beats)
+		}
+		if note.Frequency != 0 && (note.Frequency < 250 || note.Frequency > 1000) {
+			t.Errorf("Expected notes within the scale, got %v Hz", note.Frequency)
+		}
+	}
+
+	for _, length := range []int{0, -1, MaxSongLength + 1} {
+		if _, err := GodSong(1, length); !errors.Is(err, ErrInvalidSongOptions) {
+			t.Errorf("Expected ErrInvalidSongOptions for length %d, got %v", length, err)
+		}
+	}
+}
+
+func TestSongFromMessage(t *testing.T) {
+	song := SongFromMessage("God says TempleOS God")
+	if len(song) != 4 {
+		t.Fatalf("Expected one note per word, got %d", len(song))
+	}
+	if song[0] != song[3] {
+		t.Error("Expected the same word to sing the same note")
+	}
+	if song[2].Beats <= song[1].Beats {
+		t.Error("Expected longer words to be held longer")
+	}
+}
+
+func TestWriteWAV(t *testing.T) {
+	notes := []Note{{Frequency: 440, Beats: 1}, {Frequency: 0, Beats: 1}}
+	var buf bytes.Buffer
+	if err := WriteWAV(&buf, notes, SongOptions{Tempo: 120, Waveform: "sine"}); err != nil {
+		t.Fatalf("Failed to write WAV: %v", err)
+	}
+
+	data := buf.Bytes()
+	if string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" || string(data[36:40]) != "data" {
+		t.Fatalf("Expected a RIFF WAVE header, got %q", data[:44])
+	}
+	if rate := binary.LittleEndian.Uint32(data[24:28]); rate != SongSampleRate {
+		t.Errorf("Expected sample rate %d, got %d", SongSampleRate, rate)
+	}
+
+	// Two beats at 120 BPM last one second
+	dataSize := binary.LittleEndian.Uint32(data[40:44])
+	if dataSize != SongSampleRate*2 || len(data) != 44+int(dataSize) {
+		t.Errorf("Expected one second of 16-bit audio, got %d bytes", dataSize)
+	}
+	if riffSize := binary.LittleEndian.Uint32(data[4:8]); int(riffSize) != len(data)-8 {
+		t.Errorf("Expected RIFF size %d, got %d", len(data)-8, riffSize)
+	}
+
+	// The rest is silent while the tone is not
+	tone, rest := data[44:44+SongSampleRate], data[44+SongSampleRate:]
+	if bytes.Count(rest, []byte{0}) != len(rest) {
+		t.Error("Expected the rest to be silent")
+	}
+	if bytes.Count(tone, []byte{0}) == len(tone) {
+		t.Error("Expected the note to be audible")
+	}
+}
+
+func TestWriteWAVInvalidOptions(t *testing.T) {
+	notes := []Note{{Frequency: 440, Beats: 1}}
+	testCases := []SongOptions{
+		{Tempo: 10, Waveform: "sine"},
+		{Tempo: 1000, Waveform: "sine"},
+		{Tempo: 120, Waveform: "sawtooth"},
+	}
+	for _, opts := range testCases {
+		if err := WriteWAV(&bytes.Buffer{}, notes, opts); !errors.Is(err, ErrInvalidSongOptions) {
+			t.Errorf("Expected ErrInvalidSongOptions for %+v, got %v", opts, err)
+		}
+	}
+
+	long := []Note{{Frequency: 440, Beats: MaxSongSeconds * 2}}
+	if err := WriteWAV(&bytes.Buffer{}, long, SongOptions{Tempo: 60, Waveform: "sine"}); !errors.Is(err, ErrInvalidSongOptions) {
+		t.Errorf("Expected ErrInvalidSongOptions for a long song, got %v", err)
+	}
+}
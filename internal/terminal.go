@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ansiColors maps the TempleOS palette to the 16 standard ANSI foreground
+// colors, which share the CGA order except for swapped red and blue
+var ansiColors = [16]int{30, 34, 32, 36, 31, 35, 33, 37, 90, 94, 92, 96, 91, 95, 93, 97}
+
+// ansiReset restores the default terminal colors
+const ansiReset = "\x1b[0m"
+
+// TerminalOptions configures how messages are printed on a terminal
+type TerminalOptions struct {
+	// Width wraps lines at this many columns; 0 disables wrapping
+	Width int
+	// Color cycles the words through the TempleOS palette with ANSI escapes
+	Color bool
+}
+
+// FormatTerminal joins words into lines of at most opts.Width columns, like
+// WrapText, coloring each word, which may be a phrase, in turn when
+// opts.Color is set. Escape sequences do not count towards the width.
+func FormatTerminal(words []string, opts TerminalOptions) string {
+	var b strings.Builder
+	column := 0
+	for i, word := range words {
+		for j, field := range strings.Fields(word) {
+			runes := []rune(field)
+			if column > 0 && opts.Width > 0 && column+1+len(runes) > opts.Width {
+				b.WriteByte('\n')
+				column = 0
+			}
+			if column > 0 {
+				b.WriteByte(' ')
+				column++
+			}
+			// separators stay uncolored
+			if j == 0 && opts.Color {
+				fmt.Fprintf(&b, "\x1b[%dm", ansiColors[rainbowColor(i, Black)])
+			}
+			for _, r := range runes {
+				if opts.Width > 0 && column == opts.Width {
+					b.WriteByte('\n')
+					column = 0
+				}
+				b.WriteRune(r)
+				column++
+			}
+		}
+		if opts.Color && strings.TrimSpace(word) != "" {
+			b.WriteString(ansiReset)
+		}
+	}
+	return b.String()
+}
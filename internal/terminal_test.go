@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatTerminalWrap(t *testing.T) {
+	words := []string{"no news is good news", "okay", "supercalifragilistic"}
+
+	if got := FormatTerminal(words, TerminalOptions{}); got != strings.Join(words, " ") {
+		t.Errorf("Expected a single line without a width, got %q", got)
+	}
+
+	got := FormatTerminal(words, TerminalOptions{Width: 12})
+	expected := "no news is\ngood news\nokay\nsupercalifra\ngilistic"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got != strings.Join(WrapText(strings.Join(words, " "), 12), "\n") {
+		t.Error("Expected the same wrapping as WrapText")
+	}
+}
+
+func TestFormatTerminalColor(t *testing.T) {
+	words := []string{"have fun", "okay", "wonderful"}
+	got := FormatTerminal(words, TerminalOptions{Width: 10, Color: true})
+
+	expected := "\x1b[34mhave fun\x1b[0m\n\x1b[32mokay\x1b[0m\n\x1b[36mwonderful\x1b[0m"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	// escape sequences do not count towards the width
+	plain := FormatTerminal(words, TerminalOptions{Width: 10})
+	if stripped := stripANSI(got); stripped != plain {
+		t.Errorf("Expected colors not to change wrapping, got %q and %q", stripped, plain)
+	}
+}
+
+// stripANSI removes SGR escape sequences
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package internal
+
+import "strings"
+
+// WordlistStats summarizes the contents of a wordlist.
+type WordlistStats struct {
+	Entries       int     `json:"entries"`
+	UniqueEntries int     `json:"unique_entries"`
+	TotalWords    int     `json:"total_words"`
+	TotalChars    int     `json:"total_chars"`
+	AverageLength float64 `json:"average_length"`
+	Longest       string  `json:"longest"`
+	Shortest      string  `json:"shortest"`
+}
+
+// Stats returns statistics about the wordlist God speaks from.
+func (g *God) Stats() WordlistStats {
+	stats := WordlistStats{Entries: len(g.words)}
+	if len(g.words) == 0 {
+		return stats
+	}
+
+	seen := make(map[string]struct{}, len(g.words))
+	stats.Shortest = g.words[0]
+	for _, word := range g.words {
+		seen[word] = struct{}{}
+		stats.TotalWords += len(strings.Fields(word))
+		stats.TotalChars += len(word)
+		if len(word) > len(stats.Longest) {
+			stats.Longest = word
+		}
+		if len(word) < len(stats.Shortest) {
+			stats.Shortest = word
+		}
+	}
+	stats.UniqueEntries = len(seen)
+	stats.AverageLength = float64(stats.TotalChars) / float64(len(g.words))
+
+	return stats
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWords(t *testing.T) {
+	words, err := ParseWords(strings.NewReader("God\n\n  Terry  \r\nTempleOS\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse words: %v", err)
+	}
+
+	expected := []string{"God", "Terry", "TempleOS"}
+	if len(words) != len(expected) {
+		t.Fatalf("Expected %d words, got %d: %q", len(expected), len(words), words)
+	}
+	for i, word := range expected {
+		if words[i] != word {
+			t.Errorf("Expected word %d to be %q, got %q", i, word, words[i])
+		}
+	}
+}
+
+func TestNewGodWithWords(t *testing.T) {
+	god, err := NewGodWithWords([]string{"alpha", " ", "beta"}, 5)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	if god.GetWordsCount() != 2 {
+		t.Errorf("Expected 2 words, got %d", god.GetWordsCount())
+	}
+
+	for _, word := range strings.Fields(god.Speak()) {
+		if word != "alpha" && word != "beta" {
+			t.Errorf("Unexpected word %q in message", word)
+		}
+	}
+}
+
+func TestNewGodWithWordsInvalid(t *testing.T) {
+	if _, err := NewGodWithWords([]string{"", "  "}, 5); err != ErrEmptyWordlist {
+		t.Errorf("Expected ErrEmptyWordlist, got %v", err)
+	}
+
+	if _, err := NewGodWithWords([]string{"alpha"}, 0); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestGodStats(t *testing.T) {
+	god, err := NewGodWithWords([]string{"a", "bb cc", "a"}, 1)
+	if err != nil {
+		t.Fatalf("Failed to create God instance: %v", err)
+	}
+
+	stats := god.Stats()
+	if stats.Entries != 3 {
+		t.Errorf("Expected 3 entries, got %d", stats.Entries)
+	}
+	if stats.UniqueEntries != 2 {
+		t.Errorf("Expected 2 unique entries, got %d", stats.UniqueEntries)
+	}
+	if stats.TotalWords != 4 {
+		t.Errorf("Expected 4 total words, got %d", stats.TotalWords)
+	}
+	if stats.Longest != "bb cc" {
+		t.Errorf("Expected longest entry 'bb cc', got %q", stats.Longest)
+	}
+	if stats.Shortest != "a" {
+		t.Errorf("Expected shortest entry 'a', got %q", stats.Shortest)
+	}
+}